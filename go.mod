@@ -1,5 +1,5 @@
 module github.com/adnsv/go-exp
 
-go 1.19
+go 1.24
 
 require golang.org/x/exp v0.0.0-20221006183845-316c7553db56
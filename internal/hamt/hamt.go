@@ -0,0 +1,458 @@
+// Package hamt implements a persistent hash array mapped trie that backs the
+// immutable containers in the maps and sets packages.
+//
+// Every modification returns a new version that shares all the untouched
+// nodes with the previous one. Set operations between versions skip the
+// shared subtrees, which makes them proportional to the size of the
+// difference rather than to the size of the operands.
+package hamt
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+	maxShift     = 64 // nodes at this shift hold hash collisions
+)
+
+// seed is shared by all the maps, so that versions can be combined.
+var seed = maphash.MakeSeed()
+
+// Map is an immutable hash map. The zero value is an empty map.
+type Map[K comparable, V any] struct {
+	root *node[K, V]
+}
+
+// node is either a bitmap indexed node (shift < maxShift) with entries sorted
+// by their index, or a collision node (shift == maxShift) with an unordered
+// list of leaves. Nodes are canonical: a subtree holding a single leaf is
+// always inlined into its parent.
+type node[K comparable, V any] struct {
+	bitmap  uint32
+	size    int
+	entries []entry[K, V]
+}
+
+// entry is either a leaf (sub == nil) or a link to a subtree.
+type entry[K comparable, V any] struct {
+	sub  *node[K, V]
+	hash uint64
+	key  K
+	val  V
+}
+
+func hash[K comparable](k K) uint64 {
+	return maphash.Comparable(seed, k)
+}
+
+func (e *entry[K, V]) size() int {
+	if e.sub != nil {
+		return e.sub.size
+	}
+	return 1
+}
+
+func (n *node[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func index(h uint64, shift uint) (idx uint32, bit uint32) {
+	idx = uint32(h>>shift) & levelMask
+	return idx, 1 << idx
+}
+
+func (n *node[K, V]) pos(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Len returns the number of elements in m.
+func (m Map[K, V]) Len() int {
+	return m.root.len()
+}
+
+// Get returns the value associated with k.
+func (m Map[K, V]) Get(k K) (v V, ok bool) {
+	if m.root == nil {
+		return
+	}
+	e := m.root.find(hash(k), k, 0)
+	if e == nil {
+		return
+	}
+	return e.val, true
+}
+
+func (n *node[K, V]) find(h uint64, k K, shift uint) *entry[K, V] {
+	for {
+		if shift >= maxShift {
+			for i := range n.entries {
+				if n.entries[i].key == k {
+					return &n.entries[i]
+				}
+			}
+			return nil
+		}
+		_, bit := index(h, shift)
+		if n.bitmap&bit == 0 {
+			return nil
+		}
+		e := &n.entries[n.pos(bit)]
+		if e.sub == nil {
+			if e.hash == h && e.key == k {
+				return e
+			}
+			return nil
+		}
+		n = e.sub
+		shift += bitsPerLevel
+	}
+}
+
+// With returns a copy of m with k associated with v.
+func (m Map[K, V]) With(k K, v V) Map[K, V] {
+	leaf := entry[K, V]{hash: hash(k), key: k, val: v}
+	if m.root == nil {
+		return Map[K, V]{single(leaf, 0)}
+	}
+	return Map[K, V]{m.root.with(leaf, 0, true)}
+}
+
+// Without returns a copy of m without k.
+func (m Map[K, V]) Without(k K) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+	r, _ := m.root.without(hash(k), k, 0)
+	return Map[K, V]{r}
+}
+
+// All returns an iterator over the elements of m in an indeterminate order.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.all(yield)
+	}
+}
+
+func (n *node[K, V]) all(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for i := range n.entries {
+		e := &n.entries[i]
+		if e.sub != nil {
+			if !e.sub.all(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a map with the elements of both m and o. For keys that are in
+// both maps, the values from o are used. Subtrees shared by m and o are
+// reused as is.
+func (m Map[K, V]) Union(o Map[K, V]) Map[K, V] {
+	return Map[K, V]{union(m.root, o.root, 0)}
+}
+
+// Difference returns a map with the elements of m whose keys are not in o.
+// Subtrees shared by m and o are skipped.
+func (m Map[K, V]) Difference(o Map[K, V]) Map[K, V] {
+	return Map[K, V]{filter(m.root, o.root, 0, false)}
+}
+
+// Intersection returns a map with the elements of m whose keys are also in o.
+// Subtrees shared by m and o are reused as is.
+func (m Map[K, V]) Intersection(o Map[K, V]) Map[K, V] {
+	return Map[K, V]{filter(m.root, o.root, 0, true)}
+}
+
+// single returns a node that holds one leaf.
+func single[K comparable, V any](leaf entry[K, V], shift uint) *node[K, V] {
+	n := &node[K, V]{size: 1, entries: []entry[K, V]{leaf}}
+	if shift < maxShift {
+		_, n.bitmap = index(leaf.hash, shift)
+	}
+	return n
+}
+
+// pair returns a subtree that holds two leaves with different keys.
+func pair[K comparable, V any](a, b entry[K, V], shift uint) *node[K, V] {
+	if shift >= maxShift {
+		return &node[K, V]{size: 2, entries: []entry[K, V]{a, b}}
+	}
+	ia, bit_a := index(a.hash, shift)
+	ib, bit_b := index(b.hash, shift)
+	switch {
+	case ia == ib:
+		sub := pair(a, b, shift+bitsPerLevel)
+		return &node[K, V]{bitmap: bit_a, size: 2, entries: []entry[K, V]{{sub: sub}}}
+	case ia < ib:
+		return &node[K, V]{bitmap: bit_a | bit_b, size: 2, entries: []entry[K, V]{a, b}}
+	default:
+		return &node[K, V]{bitmap: bit_a | bit_b, size: 2, entries: []entry[K, V]{b, a}}
+	}
+}
+
+// with returns a copy of n with the leaf inserted. When the key is already in
+// n, its value is replaced only if overwrite is true.
+func (n *node[K, V]) with(leaf entry[K, V], shift uint, overwrite bool) *node[K, V] {
+	if shift >= maxShift {
+		for i := range n.entries {
+			if n.entries[i].key == leaf.key {
+				if !overwrite {
+					return n
+				}
+				r := n.clone()
+				r.entries[i] = leaf
+				return r
+			}
+		}
+		r := &node[K, V]{size: n.size + 1, entries: make([]entry[K, V], len(n.entries), len(n.entries)+1)}
+		copy(r.entries, n.entries)
+		r.entries = append(r.entries, leaf)
+		return r
+	}
+	_, bit := index(leaf.hash, shift)
+	p := n.pos(bit)
+	if n.bitmap&bit == 0 {
+		r := &node[K, V]{bitmap: n.bitmap | bit, size: n.size + 1, entries: make([]entry[K, V], len(n.entries)+1)}
+		copy(r.entries, n.entries[:p])
+		r.entries[p] = leaf
+		copy(r.entries[p+1:], n.entries[p:])
+		return r
+	}
+	e := &n.entries[p]
+	var replacement entry[K, V]
+	switch {
+	case e.sub != nil:
+		sub := e.sub.with(leaf, shift+bitsPerLevel, overwrite)
+		if sub == e.sub {
+			return n
+		}
+		replacement = entry[K, V]{sub: sub}
+	case e.hash == leaf.hash && e.key == leaf.key:
+		if !overwrite {
+			return n
+		}
+		replacement = leaf
+	default:
+		replacement = entry[K, V]{sub: pair(*e, leaf, shift+bitsPerLevel)}
+	}
+	r := n.clone()
+	r.size += replacement.size() - e.size()
+	r.entries[p] = replacement
+	return r
+}
+
+// without returns a copy of n with the key removed. The result may be nil if
+// n becomes empty.
+func (n *node[K, V]) without(h uint64, k K, shift uint) (*node[K, V], bool) {
+	if shift >= maxShift {
+		for i := range n.entries {
+			if n.entries[i].key == k {
+				return n.remove(i), true
+			}
+		}
+		return n, false
+	}
+	_, bit := index(h, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	p := n.pos(bit)
+	e := &n.entries[p]
+	if e.sub == nil {
+		if e.hash != h || e.key != k {
+			return n, false
+		}
+		return n.remove(p), true
+	}
+	sub, removed := e.sub.without(h, k, shift+bitsPerLevel)
+	if !removed {
+		return n, false
+	}
+	r := n.clone()
+	r.size--
+	r.entries[p] = inline(sub)
+	return r, true
+}
+
+// remove returns a copy of n without the entry at position p.
+func (n *node[K, V]) remove(p int) *node[K, V] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	r := &node[K, V]{size: n.size - n.entries[p].size(), entries: make([]entry[K, V], 0, len(n.entries)-1)}
+	r.entries = append(r.entries, n.entries[:p]...)
+	r.entries = append(r.entries, n.entries[p+1:]...)
+	if n.bitmap != 0 {
+		r.bitmap = n.bitmap &^ (1 << nthBit(n.bitmap, p))
+	}
+	return r
+}
+
+// nthBit returns the index of the p-th set bit in bitmap.
+func nthBit(bitmap uint32, p int) uint {
+	for ; p > 0; p-- {
+		bitmap &= bitmap - 1
+	}
+	return uint(bits.TrailingZeros32(bitmap))
+}
+
+func (n *node[K, V]) clone() *node[K, V] {
+	r := &node[K, V]{bitmap: n.bitmap, size: n.size, entries: make([]entry[K, V], len(n.entries))}
+	copy(r.entries, n.entries)
+	return r
+}
+
+// inline converts a non-empty subtree into an entry of its parent, keeping
+// the nodes canonical.
+func inline[K comparable, V any](sub *node[K, V]) entry[K, V] {
+	if sub.size == 1 {
+		return sub.entries[0]
+	}
+	return entry[K, V]{sub: sub}
+}
+
+// builder accumulates the entries of a new bitmap indexed node.
+type builder[K comparable, V any] struct {
+	n node[K, V]
+}
+
+func (b *builder[K, V]) add(idx uint32, e entry[K, V]) {
+	b.n.bitmap |= 1 << idx
+	b.n.size += e.size()
+	b.n.entries = append(b.n.entries, e)
+}
+
+func (b *builder[K, V]) addNode(idx uint32, sub *node[K, V]) {
+	if sub != nil {
+		b.add(idx, inline(sub))
+	}
+}
+
+func (b *builder[K, V]) node() *node[K, V] {
+	if b.n.size == 0 {
+		return nil
+	}
+	return &b.n
+}
+
+func union[K comparable, V any](a, b *node[K, V], shift uint) *node[K, V] {
+	switch {
+	case a == b || b == nil:
+		return a
+	case a == nil:
+		return b
+	case shift >= maxShift:
+		r := a
+		for i := range b.entries {
+			r = r.with(b.entries[i], shift, true)
+		}
+		return r
+	}
+	var r builder[K, V]
+	for all := a.bitmap | b.bitmap; all != 0; all &= all - 1 {
+		idx := uint32(bits.TrailingZeros32(all))
+		bit := uint32(1) << idx
+		if a.bitmap&bit == 0 {
+			r.add(idx, b.entries[b.pos(bit)])
+			continue
+		}
+		ea := a.entries[a.pos(bit)]
+		if b.bitmap&bit == 0 {
+			r.add(idx, ea)
+			continue
+		}
+		eb := b.entries[b.pos(bit)]
+		next := shift + bitsPerLevel
+		switch {
+		case ea.sub != nil && eb.sub != nil:
+			r.addNode(idx, union(ea.sub, eb.sub, next))
+		case ea.sub != nil:
+			r.addNode(idx, ea.sub.with(eb, next, true))
+		case eb.sub != nil:
+			r.addNode(idx, eb.sub.with(ea, next, false))
+		case ea.hash == eb.hash && ea.key == eb.key:
+			r.add(idx, eb)
+		default:
+			r.addNode(idx, pair(ea, eb, next))
+		}
+	}
+	return r.node()
+}
+
+// filter returns the elements of a whose keys are in b (keep_common) or are
+// not in b (!keep_common).
+func filter[K comparable, V any](a, b *node[K, V], shift uint, keep_common bool) *node[K, V] {
+	switch {
+	case a == nil:
+		return nil
+	case a == b:
+		if keep_common {
+			return a
+		}
+		return nil
+	case b == nil:
+		if keep_common {
+			return nil
+		}
+		return a
+	case shift >= maxShift:
+		var r *node[K, V]
+		for i := range a.entries {
+			if (b.find(a.entries[i].hash, a.entries[i].key, shift) != nil) == keep_common {
+				if r == nil {
+					r = single(a.entries[i], shift)
+				} else {
+					r = r.with(a.entries[i], shift, true)
+				}
+			}
+		}
+		return r
+	}
+	var r builder[K, V]
+	for all := a.bitmap; all != 0; all &= all - 1 {
+		idx := uint32(bits.TrailingZeros32(all))
+		bit := uint32(1) << idx
+		ea := a.entries[a.pos(bit)]
+		if b.bitmap&bit == 0 {
+			if !keep_common {
+				r.add(idx, ea)
+			}
+			continue
+		}
+		eb := b.entries[b.pos(bit)]
+		next := shift + bitsPerLevel
+		switch {
+		case ea.sub != nil && eb.sub != nil:
+			r.addNode(idx, filter(ea.sub, eb.sub, next, keep_common))
+		case ea.sub != nil:
+			if keep_common {
+				if e := ea.sub.find(eb.hash, eb.key, next); e != nil {
+					r.add(idx, *e)
+				}
+			} else {
+				sub, _ := ea.sub.without(eb.hash, eb.key, next)
+				r.addNode(idx, sub)
+			}
+		case eb.sub != nil:
+			if (eb.sub.find(ea.hash, ea.key, next) != nil) == keep_common {
+				r.add(idx, ea)
+			}
+		case (ea.hash == eb.hash && ea.key == eb.key) == keep_common:
+			r.add(idx, ea)
+		}
+	}
+	return r.node()
+}
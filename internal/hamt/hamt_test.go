@@ -0,0 +1,208 @@
+package hamt
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+// check verifies the size, bitmap and canonical form invariants of the
+// subtree rooted at n.
+func check[K comparable, V any](t *testing.T, n *node[K, V], shift uint) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if shift < maxShift && bits.OnesCount32(n.bitmap) != len(n.entries) {
+		t.Fatalf("bitmap does not match entries at shift %d", shift)
+	}
+	size := 0
+	for i := range n.entries {
+		e := &n.entries[i]
+		if e.sub != nil {
+			if e.sub.size < 2 {
+				t.Fatalf("subtree with %d elements is not inlined", e.sub.size)
+			}
+			size += check(t, e.sub, shift+bitsPerLevel)
+			continue
+		}
+		size++
+		if shift < maxShift && n.pos(1<<(uint32(e.hash>>shift)&levelMask)) != i {
+			t.Fatalf("misplaced leaf %v", e.key)
+		}
+	}
+	if n.size != size {
+		t.Fatalf("size is %d, want %d", n.size, size)
+	}
+	return size
+}
+
+func collect[K comparable, V any](m Map[K, V]) map[K]V {
+	r := map[K]V{}
+	for k, v := range m.All() {
+		r[k] = v
+	}
+	return r
+}
+
+func TestRandomized(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var m Map[int, int]
+	ref := map[int]int{}
+	versions := []Map[int, int]{}
+	refs := []map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := rnd.Intn(700)
+		if rnd.Intn(3) == 0 {
+			m = m.Without(k)
+			delete(ref, k)
+		} else {
+			m = m.With(k, i)
+			ref[k] = i
+		}
+		if i%500 == 0 {
+			versions = append(versions, m)
+			refs = append(refs, maps.Clone(ref))
+		}
+	}
+	check(t, m.root, 0)
+	if m.Len() != len(ref) || !maps.Equal(collect(m), ref) {
+		t.Fatal("map does not match the reference")
+	}
+	for k := 0; k < 700; k++ {
+		v, ok := m.Get(k)
+		if want, exists := ref[k]; ok != exists || v != want {
+			t.Fatalf("Get(%d) = %d, %v", k, v, ok)
+		}
+	}
+	// older versions are not affected by the later changes
+	for i, v := range versions {
+		if !maps.Equal(collect(v), refs[i]) {
+			t.Fatalf("version %d was modified", i)
+		}
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	var base Map[int, int]
+	for i := 0; i < 2000; i++ {
+		base = base.With(i, i)
+	}
+	derive := func() Map[int, int] {
+		m := base
+		for i := 0; i < 300; i++ {
+			k := rnd.Intn(2500)
+			if rnd.Intn(2) == 0 {
+				m = m.Without(k)
+			} else {
+				m = m.With(k, -k)
+			}
+		}
+		return m
+	}
+	for i := 0; i < 20; i++ {
+		a, b := derive(), derive()
+		ra, rb := collect(a), collect(b)
+
+		u := a.Union(b)
+		check(t, u.root, 0)
+		want := maps.Clone(ra)
+		maps.Copy(want, rb)
+		if u.Len() != len(want) || !maps.Equal(collect(u), want) {
+			t.Fatal("bad union")
+		}
+
+		d := a.Difference(b)
+		check(t, d.root, 0)
+		want = map[int]int{}
+		for k, v := range ra {
+			if _, ok := rb[k]; !ok {
+				want[k] = v
+			}
+		}
+		if d.Len() != len(want) || !maps.Equal(collect(d), want) {
+			t.Fatal("bad difference")
+		}
+
+		n := a.Intersection(b)
+		check(t, n.root, 0)
+		want = map[int]int{}
+		for k, v := range ra {
+			if _, ok := rb[k]; ok {
+				want[k] = v
+			}
+		}
+		if n.Len() != len(want) || !maps.Equal(collect(n), want) {
+			t.Fatal("bad intersection")
+		}
+	}
+}
+
+func TestSharing(t *testing.T) {
+	var m Map[int, int]
+	for i := 0; i < 1000; i++ {
+		m = m.With(i, i)
+	}
+	if u := m.Union(m); u.root != m.root {
+		t.Error("union with itself is not shared")
+	}
+	if n := m.Intersection(m); n.root != m.root {
+		t.Error("intersection with itself is not shared")
+	}
+	if d := m.Difference(m); d.Len() != 0 {
+		t.Error("difference with itself is not empty")
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	const h = 42
+	var r *node[string, int]
+	keys := []string{"a", "b", "c", "d"}
+	for i, k := range keys {
+		leaf := entry[string, int]{hash: h, key: k, val: i}
+		if r == nil {
+			r = single(leaf, 0)
+		} else {
+			r = r.with(leaf, 0, true)
+		}
+	}
+	check(t, r, 0)
+	if r.size != 4 {
+		t.Fatalf("size is %d", r.size)
+	}
+	for i, k := range keys {
+		if e := r.find(h, k, 0); e == nil || e.val != i {
+			t.Fatalf("missing %s", k)
+		}
+	}
+	if r.find(h, "x", 0) != nil {
+		t.Fatal("found a missing key")
+	}
+	r2, ok := r.without(h, "b", 0)
+	if !ok || r2.size != 3 || r2.find(h, "b", 0) != nil {
+		t.Fatal("bad removal")
+	}
+	check(t, r2, 0)
+
+	a, b := Map[string, int]{r}, Map[string, int]{r2}
+	if d := a.Difference(b); d.Len() != 1 {
+		t.Fatalf("difference has %d elements", d.Len())
+	} else {
+		check(t, d.root, 0)
+	}
+	if n := a.Intersection(b); n.Len() != 3 {
+		t.Fatalf("intersection has %d elements", n.Len())
+	}
+	if u := b.Union(a); u.Len() != 4 {
+		t.Fatalf("union has %d elements", u.Len())
+	}
+	for _, k := range []string{"a", "c", "d"} {
+		r2, _ = r2.without(h, k, 0)
+	}
+	if r2 != nil {
+		t.Fatal("map is not empty")
+	}
+}
@@ -0,0 +1,390 @@
+// Package tree implements an order-statistic AVL tree that backs the sorted
+// containers in the maps and sets packages.
+package tree
+
+import "iter"
+
+// Tree is an AVL tree of key-value pairs ordered by the less function. Every
+// node keeps the size of its subtree which enables O(log n) Rank and Select.
+//
+// The zero value is an empty tree without a less function. It can be read and
+// cleared, but keys can only be added to a tree created with New or Build.
+type Tree[K, V any] struct {
+	root *node[K, V]
+	less func(a, b K) bool
+}
+
+type node[K, V any] struct {
+	key         K
+	val         V
+	left, right *node[K, V]
+	height      int8
+	size        int
+}
+
+// New returns an empty tree ordered by the less function.
+func New[K, V any](less func(a, b K) bool) *Tree[K, V] {
+	return &Tree[K, V]{less: less}
+}
+
+// Build returns a perfectly balanced tree constructed in O(n) from keys that
+// are already sorted and contain no duplicates. The vals slice may be nil,
+// otherwise it must have the same length as keys.
+func Build[K, V any](less func(a, b K) bool, keys []K, vals []V) *Tree[K, V] {
+	return &Tree[K, V]{root: build(keys, vals), less: less}
+}
+
+func build[K, V any](keys []K, vals []V) *node[K, V] {
+	if len(keys) == 0 {
+		return nil
+	}
+	mid := len(keys) / 2
+	n := &node[K, V]{key: keys[mid]}
+	if vals != nil {
+		n.val = vals[mid]
+		n.left = build(keys[:mid], vals[:mid])
+		n.right = build(keys[mid+1:], vals[mid+1:])
+	} else {
+		n.left = build[K, V](keys[:mid], nil)
+		n.right = build[K, V](keys[mid+1:], nil)
+	}
+	n.update()
+	return n
+}
+
+// Less returns the ordering function of t.
+func (t *Tree[K, V]) Less() func(a, b K) bool {
+	return t.less
+}
+
+// Len returns the number of nodes in t.
+func (t *Tree[K, V]) Len() int {
+	return t.root.len()
+}
+
+// Clear removes all nodes from t.
+func (t *Tree[K, V]) Clear() {
+	t.root = nil
+}
+
+// Get returns the value associated with k.
+func (t *Tree[K, V]) Get(k K) (v V, ok bool) {
+	n := t.find(k)
+	if n == nil {
+		return
+	}
+	return n.val, true
+}
+
+// Put associates v with k. Returns true if k was not in t before.
+func (t *Tree[K, V]) Put(k K, v V) (added bool) {
+	t.root, added = t.put(t.root, k, v)
+	return
+}
+
+// Delete removes k from t. Returns the removed value, if any.
+func (t *Tree[K, V]) Delete(k K) (v V, ok bool) {
+	t.root, v, ok = t.delete(t.root, k)
+	return
+}
+
+// Min returns the smallest key in t.
+func (t *Tree[K, V]) Min() (k K, v V, ok bool) {
+	n := t.root
+	if n == nil {
+		return
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.val, true
+}
+
+// Max returns the largest key in t.
+func (t *Tree[K, V]) Max() (k K, v V, ok bool) {
+	n := t.root
+	if n == nil {
+		return
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.val, true
+}
+
+// Floor returns the largest key that is less than or equal to k.
+func (t *Tree[K, V]) Floor(k K) (K, V, bool) {
+	return t.below(k, true)
+}
+
+// Lower returns the largest key that is strictly less than k.
+func (t *Tree[K, V]) Lower(k K) (K, V, bool) {
+	return t.below(k, false)
+}
+
+// Ceiling returns the smallest key that is greater than or equal to k.
+func (t *Tree[K, V]) Ceiling(k K) (K, V, bool) {
+	return t.above(k, true)
+}
+
+// Higher returns the smallest key that is strictly greater than k.
+func (t *Tree[K, V]) Higher(k K) (K, V, bool) {
+	return t.above(k, false)
+}
+
+// Rank returns the number of keys in t that are strictly less than k.
+func (t *Tree[K, V]) Rank(k K) int {
+	r := 0
+	for n := t.root; n != nil; {
+		switch {
+		case t.less(k, n.key):
+			n = n.left
+		case t.less(n.key, k):
+			r += n.left.len() + 1
+			n = n.right
+		default:
+			return r + n.left.len()
+		}
+	}
+	return r
+}
+
+// Select returns the i-th smallest key (counting from zero).
+func (t *Tree[K, V]) Select(i int) (k K, v V, ok bool) {
+	if i < 0 || i >= t.Len() {
+		return
+	}
+	n := t.root
+	for {
+		l := n.left.len()
+		switch {
+		case i < l:
+			n = n.left
+		case i > l:
+			i -= l + 1
+			n = n.right
+		default:
+			return n.key, n.val, true
+		}
+	}
+}
+
+// All returns an iterator over all the nodes in ascending order.
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.ascend(yield)
+	}
+}
+
+// Backward returns an iterator over all the nodes in descending order.
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the nodes with keys in the half-open
+// interval [lo, hi) in ascending order.
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.ascendRange(t.root, lo, hi, yield)
+	}
+}
+
+func (t *Tree[K, V]) find(k K) *node[K, V] {
+	n := t.root
+	for n != nil {
+		switch {
+		case t.less(k, n.key):
+			n = n.left
+		case t.less(n.key, k):
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+func (t *Tree[K, V]) below(k K, inclusive bool) (rk K, rv V, ok bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		switch {
+		case t.less(n.key, k):
+			best = n
+			n = n.right
+		case t.less(k, n.key):
+			n = n.left
+		case inclusive:
+			return n.key, n.val, true
+		default:
+			n = n.left
+		}
+	}
+	if best == nil {
+		return
+	}
+	return best.key, best.val, true
+}
+
+func (t *Tree[K, V]) above(k K, inclusive bool) (rk K, rv V, ok bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		switch {
+		case t.less(k, n.key):
+			best = n
+			n = n.left
+		case t.less(n.key, k):
+			n = n.right
+		case inclusive:
+			return n.key, n.val, true
+		default:
+			n = n.right
+		}
+	}
+	if best == nil {
+		return
+	}
+	return best.key, best.val, true
+}
+
+func (t *Tree[K, V]) ascendRange(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	if t.less(n.key, lo) {
+		return t.ascendRange(n.right, lo, hi, yield)
+	}
+	if !t.less(n.key, hi) {
+		return t.ascendRange(n.left, lo, hi, yield)
+	}
+	return t.ascendRange(n.left, lo, hi, yield) &&
+		yield(n.key, n.val) &&
+		t.ascendRange(n.right, lo, hi, yield)
+}
+
+func (t *Tree[K, V]) put(n *node[K, V], k K, v V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{key: k, val: v, height: 1, size: 1}, true
+	}
+	var added bool
+	switch {
+	case t.less(k, n.key):
+		n.left, added = t.put(n.left, k, v)
+	case t.less(n.key, k):
+		n.right, added = t.put(n.right, k, v)
+	default:
+		n.val = v
+		return n, false
+	}
+	return n.rebalance(), added
+}
+
+func (t *Tree[K, V]) delete(n *node[K, V], k K) (*node[K, V], V, bool) {
+	var v V
+	if n == nil {
+		return nil, v, false
+	}
+	var ok bool
+	switch {
+	case t.less(k, n.key):
+		n.left, v, ok = t.delete(n.left, k)
+	case t.less(n.key, k):
+		n.right, v, ok = t.delete(n.right, k)
+	default:
+		v, ok = n.val, true
+		if n.left == nil {
+			return n.right, v, true
+		}
+		if n.right == nil {
+			return n.left, v, true
+		}
+		var min *node[K, V]
+		n.right, min = n.right.deleteMin()
+		min.left, min.right = n.left, n.right
+		n = min
+	}
+	if !ok {
+		return n, v, false
+	}
+	return n.rebalance(), v, true
+}
+
+// node helpers
+
+func (n *node[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) h() int8 {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[K, V]) update() {
+	n.height = max(n.left.h(), n.right.h()) + 1
+	n.size = n.left.len() + n.right.len() + 1
+}
+
+func (n *node[K, V]) rotateLeft() *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *node[K, V]) rotateRight() *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *node[K, V]) rebalance() *node[K, V] {
+	n.update()
+	switch balance := n.left.h() - n.right.h(); {
+	case balance > 1:
+		if n.left.left.h() < n.left.right.h() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.right.h() < n.right.left.h() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// deleteMin detaches the smallest node from the subtree rooted at n.
+func (n *node[K, V]) deleteMin() (root, min *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	n.left, min = n.left.deleteMin()
+	return n.rebalance(), min
+}
+
+func (n *node[K, V]) ascend(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.ascend(yield) && yield(n.key, n.val) && n.right.ascend(yield)
+}
+
+func (n *node[K, V]) descend(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.right.descend(yield) && yield(n.key, n.val) && n.left.descend(yield)
+}
@@ -0,0 +1,124 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func less(a, b int) bool { return a < b }
+
+// check verifies the AVL and size invariants of the subtree rooted at n.
+func check[K, V any](t *testing.T, tr *Tree[K, V], n *node[K, V]) {
+	t.Helper()
+	if n == nil {
+		return
+	}
+	check(t, tr, n.left)
+	check(t, tr, n.right)
+	if n.size != n.left.len()+n.right.len()+1 {
+		t.Fatalf("bad size at %v", n.key)
+	}
+	if b := n.left.h() - n.right.h(); b < -1 || b > 1 {
+		t.Fatalf("unbalanced at %v", n.key)
+	}
+	if n.left != nil && !tr.less(n.left.key, n.key) || n.right != nil && !tr.less(n.key, n.right.key) {
+		t.Fatalf("bad order at %v", n.key)
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tr := New[int, int](less)
+	ref := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := rnd.Intn(500)
+		if rnd.Intn(3) == 0 {
+			_, ok := tr.Delete(k)
+			_, want := ref[k]
+			if ok != want {
+				t.Fatalf("Delete(%d) = %v, want %v", k, ok, want)
+			}
+			delete(ref, k)
+		} else {
+			added := tr.Put(k, i)
+			_, exists := ref[k]
+			if added == exists {
+				t.Fatalf("Put(%d) = %v, want %v", k, added, !exists)
+			}
+			ref[k] = i
+		}
+	}
+	check(t, tr, tr.root)
+
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if tr.Len() != len(keys) {
+		t.Fatalf("Len() = %d, want %d", tr.Len(), len(keys))
+	}
+
+	var got []int
+	for k, v := range tr.All() {
+		if v != ref[k] {
+			t.Fatalf("value of %d = %d, want %d", k, v, ref[k])
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, keys) {
+		t.Fatalf("All() = %v, want %v", got, keys)
+	}
+
+	for i, k := range keys {
+		if r := tr.Rank(k); r != i {
+			t.Fatalf("Rank(%d) = %d, want %d", k, r, i)
+		}
+		if sk, _, ok := tr.Select(i); !ok || sk != k {
+			t.Fatalf("Select(%d) = %d, want %d", i, sk, k)
+		}
+	}
+
+	for q := -1; q <= 501; q++ {
+		i, found := slices.BinarySearch(keys, q)
+		wantFloor, wantCeil := i-1, i
+		if found {
+			wantFloor = i
+		}
+		if k, _, ok := tr.Floor(q); ok != (wantFloor >= 0) || ok && k != keys[wantFloor] {
+			t.Fatalf("Floor(%d) = %d, %v", q, k, ok)
+		}
+		if k, _, ok := tr.Ceiling(q); ok != (wantCeil < len(keys)) || ok && k != keys[wantCeil] {
+			t.Fatalf("Ceiling(%d) = %d, %v", q, k, ok)
+		}
+	}
+
+	var ranged []int
+	for k := range tr.Range(100, 200) {
+		ranged = append(ranged, k)
+	}
+	lo, _ := slices.BinarySearch(keys, 100)
+	hi, _ := slices.BinarySearch(keys, 200)
+	if !slices.Equal(ranged, keys[lo:hi]) {
+		t.Fatalf("Range(100, 200) = %v, want %v", ranged, keys[lo:hi])
+	}
+}
+
+func TestBuild(t *testing.T) {
+	for n := 0; n < 50; n++ {
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = i * 2
+		}
+		tr := Build[int, struct{}](less, keys, nil)
+		check(t, tr, tr.root)
+		if tr.Len() != n {
+			t.Fatalf("Len() = %d, want %d", tr.Len(), n)
+		}
+		tr.Put(-1, struct{}{})
+		tr.Delete(0)
+		check(t, tr, tr.root)
+	}
+}
@@ -0,0 +1,218 @@
+// Package trie implements a radix tree keyed by strings that backs the trie
+// containers in the maps and sets packages.
+package trie
+
+import (
+	"iter"
+	"sort"
+	"strings"
+)
+
+// Trie is a radix tree: a prefix tree where chains of nodes with a single
+// child are collapsed into one edge. Iteration visits the keys in
+// lexicographic byte order. The zero value is an empty tree ready to use.
+type Trie[V any] struct {
+	root node[V]
+	size int
+}
+
+// node is reached from its parent by an edge labeled with a non-empty part of
+// the key. Except for the root, every node either holds a value or has at
+// least two children.
+type node[V any] struct {
+	label    string
+	val      V
+	has      bool
+	children []*node[V] // sorted by the first byte of their labels
+}
+
+// child returns the position of the child whose label starts with b, or where
+// it would be inserted.
+func (n *node[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].label[0] >= b })
+	return i, i < len(n.children) && n.children[i].label[0] == b
+}
+
+// merge collapses n with its only child.
+func (n *node[V]) merge() {
+	c := n.children[0]
+	n.label += c.label
+	n.val, n.has, n.children = c.val, c.has, c.children
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Len returns the number of keys in t.
+func (t *Trie[V]) Len() int {
+	return t.size
+}
+
+// Clear removes all keys from t.
+func (t *Trie[V]) Clear() {
+	*t = Trie[V]{}
+}
+
+// find returns the node for the key, or nil.
+func (t *Trie[V]) find(key string) *node[V] {
+	n := &t.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok || !strings.HasPrefix(key, n.children[i].label) {
+			return nil
+		}
+		n = n.children[i]
+		key = key[len(n.label):]
+	}
+	return n
+}
+
+// Get returns the value associated with the key.
+func (t *Trie[V]) Get(key string) (v V, ok bool) {
+	if n := t.find(key); n != nil && n.has {
+		return n.val, true
+	}
+	return
+}
+
+// Put associates the value v with the key. Returns true if the key was not in
+// t before.
+func (t *Trie[V]) Put(key string, v V) bool {
+	n := &t.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok {
+			leaf := &node[V]{label: key, val: v, has: true}
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = leaf
+			t.size++
+			return true
+		}
+		c := n.children[i]
+		if p := commonPrefix(key, c.label); p < len(c.label) {
+			// split the edge
+			mid := &node[V]{label: c.label[:p], children: []*node[V]{c}}
+			c.label = c.label[p:]
+			n.children[i] = mid
+			c = mid
+		}
+		key = key[len(c.label):]
+		n = c
+	}
+	added := !n.has
+	n.val, n.has = v, true
+	if added {
+		t.size++
+	}
+	return added
+}
+
+// Delete removes the key from t and returns its value.
+func (t *Trie[V]) Delete(key string) (v V, ok bool) {
+	var parent *node[V]
+	var pos int
+	n := &t.root
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok || !strings.HasPrefix(key, n.children[i].label) {
+			return v, false
+		}
+		parent, pos, n = n, i, n.children[i]
+		key = key[len(n.label):]
+	}
+	if !n.has {
+		return v, false
+	}
+	v = n.val
+	var zero V
+	n.val, n.has = zero, false
+	t.size--
+	switch {
+	case parent == nil:
+		// the root keeps its place
+	case len(n.children) == 0:
+		parent.children = append(parent.children[:pos], parent.children[pos+1:]...)
+		if parent != &t.root && !parent.has && len(parent.children) == 1 {
+			parent.merge()
+		}
+	case len(n.children) == 1:
+		n.merge()
+	}
+	return v, true
+}
+
+// All returns an iterator over the keys and values of t in lexicographic
+// order.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.root.walk(nil, yield)
+	}
+}
+
+// walk visits the subtree of n, buf holds the key of the parent of n.
+func (n *node[V]) walk(buf []byte, yield func(string, V) bool) bool {
+	buf = append(buf, n.label...)
+	if n.has && !yield(string(buf), n.val) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walk(buf, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithPrefix returns an iterator over the keys of t that start with prefix
+// and their values, in lexicographic order.
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := &t.root
+		key := ""
+		for prefix != "" {
+			i, ok := n.child(prefix[0])
+			if !ok {
+				return
+			}
+			c := n.children[i]
+			if strings.HasPrefix(c.label, prefix) {
+				c.walk([]byte(key), yield)
+				return
+			}
+			if !strings.HasPrefix(prefix, c.label) {
+				return
+			}
+			key += c.label
+			prefix = prefix[len(c.label):]
+			n = c
+		}
+		n.walk([]byte(key[:len(key)-len(n.label)]), yield)
+	}
+}
+
+// LongestPrefix returns the longest key in t that is a prefix of s.
+func (t *Trie[V]) LongestPrefix(s string) (key string, v V, ok bool) {
+	n := &t.root
+	if n.has {
+		v, ok = n.val, true
+	}
+	matched := 0
+	for matched < len(s) {
+		i, found := n.child(s[matched])
+		if !found || !strings.HasPrefix(s[matched:], n.children[i].label) {
+			break
+		}
+		n = n.children[i]
+		matched += len(n.label)
+		if n.has {
+			key, v, ok = s[:matched], n.val, true
+		}
+	}
+	return
+}
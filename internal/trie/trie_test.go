@@ -0,0 +1,127 @@
+package trie
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// check verifies the structural invariants of the subtree rooted at n and
+// returns the number of values in it.
+func check[V any](t *testing.T, n *node[V], root bool) int {
+	t.Helper()
+	if !root && n.label == "" {
+		t.Fatalf("empty label")
+	}
+	if !root && !n.has && len(n.children) < 2 {
+		t.Fatalf("node %q without a value has %d children", n.label, len(n.children))
+	}
+	size := 0
+	if n.has {
+		size++
+	}
+	for i, c := range n.children {
+		if i > 0 && n.children[i-1].label[0] >= c.label[0] {
+			t.Fatalf("children of %q are not sorted", n.label)
+		}
+		size += check(t, c, false)
+	}
+	return size
+}
+
+func randomKey(rnd *rand.Rand) string {
+	var b strings.Builder
+	for i := rnd.Intn(6); i > 0; i-- {
+		b.WriteByte("abc"[rnd.Intn(3)])
+	}
+	return b.String()
+}
+
+func TestRandomized(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var tr Trie[int]
+	ref := map[string]int{}
+	for i := 0; i < 5000; i++ {
+		k := randomKey(rnd)
+		if rnd.Intn(3) == 0 {
+			_, ok := tr.Delete(k)
+			_, want := ref[k]
+			if ok != want {
+				t.Fatalf("Delete(%q) = %v, want %v", k, ok, want)
+			}
+			delete(ref, k)
+		} else {
+			_, exists := ref[k]
+			if added := tr.Put(k, i); added == exists {
+				t.Fatalf("Put(%q) = %v", k, added)
+			}
+			ref[k] = i
+		}
+		if n := check(t, &tr.root, true); n != len(ref) || tr.Len() != len(ref) {
+			t.Fatalf("size is %d/%d, want %d", n, tr.Len(), len(ref))
+		}
+	}
+
+	var keys []string
+	for k, v := range tr.All() {
+		if ref[k] != v {
+			t.Fatalf("All() yielded %q: %d, want %d", k, v, ref[k])
+		}
+		keys = append(keys, k)
+	}
+	want := maps.Keys(ref)
+	slices.Sort(want)
+	if !slices.Equal(keys, want) {
+		t.Fatalf("All() = %v, want %v", keys, want)
+	}
+
+	for i := 0; i < 200; i++ {
+		p := randomKey(rnd)
+		var got, want []string
+		for k := range tr.WithPrefix(p) {
+			got = append(got, k)
+		}
+		for _, k := range keys {
+			if strings.HasPrefix(k, p) {
+				want = append(want, k)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("WithPrefix(%q) = %v, want %v", p, got, want)
+		}
+
+		s := p + randomKey(rnd)
+		lk, lv, ok := tr.LongestPrefix(s)
+		want_k, want_ok := "", false
+		for _, k := range keys {
+			if strings.HasPrefix(s, k) && len(k) >= len(want_k) {
+				want_k, want_ok = k, true
+			}
+		}
+		if ok != want_ok || lk != want_k || ok && lv != ref[lk] {
+			t.Fatalf("LongestPrefix(%q) = %q, %v, want %q, %v", s, lk, ok, want_k, want_ok)
+		}
+	}
+}
+
+func TestEmptyKey(t *testing.T) {
+	var tr Trie[int]
+	tr.Put("", 1)
+	tr.Put("a", 2)
+	if k, v, ok := tr.LongestPrefix("b"); !ok || k != "" || v != 1 {
+		t.Errorf("LongestPrefix() = %q, %d, %v", k, v, ok)
+	}
+	if v, ok := tr.Delete(""); !ok || v != 1 || tr.Len() != 1 {
+		t.Errorf("Delete() = %d, %v", v, ok)
+	}
+	if _, ok := tr.Get(""); ok {
+		t.Errorf("the empty key was not deleted")
+	}
+	tr.Clear()
+	if tr.Len() != 0 {
+		t.Errorf("Clear() left %d keys", tr.Len())
+	}
+}
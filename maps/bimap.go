@@ -0,0 +1,146 @@
+package maps
+
+import "iter"
+
+// BiMap is a bidirectional map that enforces a one-to-one mapping between
+// keys and values. It maintains both the forward and the inverse index, so
+// that lookups by value take O(1), and the two indexes can never diverge.
+//
+// The zero value is an empty map ready to use. A BiMap is not safe for
+// concurrent use.
+type BiMap[K comparable, V comparable] struct {
+	fwd     map[K]V
+	inv     map[V]K
+	inverse *BiMap[V, K]
+}
+
+// BiMapFrom returns a BiMap constructed from m. Values that are associated
+// with more than one key in m can not be mapped one-to-one, they are excluded
+// from the result and returned as a set of duplicates, the same way Inverted
+// reports them.
+func BiMapFrom[M ~map[K]V, K comparable, V comparable](m M) (b *BiMap[K, V], duplicates map[V]struct{}) {
+	inv, duplicates := Inverted(m)
+	fwd := make(map[K]V, len(inv))
+	for v, k := range inv {
+		fwd[k] = v
+	}
+	b = &BiMap[K, V]{fwd: fwd, inv: inv}
+	return
+}
+
+func (b *BiMap[K, V]) lazyInit() {
+	if b.fwd == nil {
+		b.fwd = map[K]V{}
+		b.inv = map[V]K{}
+	}
+}
+
+// Len returns the number of key-value pairs in b.
+func (b *BiMap[K, V]) Len() int {
+	return len(b.fwd)
+}
+
+// Get returns the value associated with the key k.
+func (b *BiMap[K, V]) Get(k K) (v V, ok bool) {
+	v, ok = b.fwd[k]
+	return
+}
+
+// GetKey returns the key associated with the value v.
+func (b *BiMap[K, V]) GetKey(v V) (k K, ok bool) {
+	k, ok = b.inv[v]
+	return
+}
+
+// HasKey checks if there is a key k in b.
+func (b *BiMap[K, V]) HasKey(k K) bool {
+	_, ok := b.fwd[k]
+	return ok
+}
+
+// HasValue checks if there is a value v in b.
+func (b *BiMap[K, V]) HasValue(v V) bool {
+	_, ok := b.inv[v]
+	return ok
+}
+
+// Insert associates k with v, if neither of them is already in use. Returns
+// false and leaves b unchanged if k is associated with another value, or v is
+// associated with another key. Inserting a pair that is already in b is a no-op
+// that returns true.
+func (b *BiMap[K, V]) Insert(k K, v V) bool {
+	if prev_v, exists := b.fwd[k]; exists {
+		return prev_v == v
+	}
+	if _, exists := b.inv[v]; exists {
+		return false
+	}
+	b.lazyInit()
+	b.fwd[k] = v
+	b.inv[v] = k
+	return true
+}
+
+// Set associates k with v, removing any previous mapping of k and any previous
+// mapping of v.
+func (b *BiMap[K, V]) Set(k K, v V) {
+	b.Delete(k)
+	b.DeleteValue(v)
+	b.lazyInit()
+	b.fwd[k] = v
+	b.inv[v] = k
+}
+
+// Delete removes the key k and its value from b. Returns false if there was no
+// such key.
+func (b *BiMap[K, V]) Delete(k K) bool {
+	v, ok := b.fwd[k]
+	if ok {
+		delete(b.fwd, k)
+		delete(b.inv, v)
+	}
+	return ok
+}
+
+// DeleteValue removes the value v and its key from b. Returns false if there
+// was no such value.
+func (b *BiMap[K, V]) DeleteValue(v V) bool {
+	k, ok := b.inv[v]
+	if ok {
+		delete(b.inv, v)
+		delete(b.fwd, k)
+	}
+	return ok
+}
+
+// Clear removes all key-value pairs from b.
+func (b *BiMap[K, V]) Clear() {
+	clear(b.fwd)
+	clear(b.inv)
+}
+
+// Inverse returns a view of b with keys and values swapped. The view shares
+// the indexes with b: changes made through either of them are visible in
+// both.
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	if b.inverse == nil {
+		b.lazyInit()
+		b.inverse = &BiMap[V, K]{fwd: b.inv, inv: b.fwd, inverse: b}
+	}
+	return b.inverse
+}
+
+// All returns an iterator over key-value pairs in b. The pairs will be in an
+// indeterminate order.
+func (b *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return All(b.fwd)
+}
+
+// Map returns a plain map constructed from b.
+func (b *BiMap[K, V]) Map() map[K]V {
+	r := make(map[K]V, len(b.fwd))
+	for k, v := range b.fwd {
+		r[k] = v
+	}
+	return r
+}
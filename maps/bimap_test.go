@@ -0,0 +1,51 @@
+package maps
+
+import "testing"
+
+func TestBiMapConsistency(t *testing.T) {
+	b := BiMap[string, int]{}
+	inv := b.Inverse()
+
+	check := func(name string, want map[string]int) {
+		t.Helper()
+		if b.Len() != len(want) || inv.Len() != len(want) {
+			t.Fatalf("%s: Len() = %d/%d, want %d", name, b.Len(), inv.Len(), len(want))
+		}
+		for k, v := range want {
+			if got, ok := b.Get(k); !ok || got != v {
+				t.Errorf("%s: Get(%q) = %d, %v, want %d", name, k, got, ok, v)
+			}
+			if got, ok := inv.Get(v); !ok || got != k {
+				t.Errorf("%s: Inverse().Get(%d) = %q, %v, want %q", name, v, got, ok, k)
+			}
+		}
+	}
+
+	b.Insert("a", 1)
+	b.Insert("b", 2)
+	check("insert", map[string]int{"a": 1, "b": 2})
+
+	if b.Insert("a", 3) || b.Insert("c", 2) {
+		t.Errorf("Insert() = true for a key or value that is in use")
+	}
+	if !b.Insert("a", 1) {
+		t.Errorf("Insert() = false for an existing pair")
+	}
+	check("rejected insert", map[string]int{"a": 1, "b": 2})
+
+	b.Set("a", 2)
+	check("set steals value", map[string]int{"a": 2})
+
+	inv.Set(5, "e")
+	check("set through inverse", map[string]int{"a": 2, "e": 5})
+
+	if inv.Inverse() != &b {
+		t.Errorf("Inverse().Inverse() is not the original map")
+	}
+
+	inv.Delete(2)
+	check("delete through inverse", map[string]int{"e": 5})
+
+	b.DeleteValue(5)
+	check("delete value", map[string]int{})
+}
@@ -0,0 +1,83 @@
+package maps
+
+import "time"
+
+// CacheOptions configures the optional behavior of LRU and LFU caches.
+type CacheOptions[K comparable, V any] struct {
+	// TTL is how long an element stays in the cache after it was last set.
+	// Zero means that elements never expire.
+	TTL time.Duration
+	// Now returns the current time, time.Now is used if nil. Tests can
+	// replace it with a fake clock.
+	Now func() time.Time
+	// OnEvict, if not nil, is called when an element is removed from the
+	// cache to make room for a new one or because it has expired. It is not
+	// called for Delete and Clear.
+	OnEvict func(k K, v V)
+}
+
+// CacheStats holds the counters of a cache.
+type CacheStats struct {
+	Hits      int // lookups that found a live element
+	Misses    int // lookups that found nothing or an expired element
+	Evictions int // elements removed by the capacity limit or expiration
+}
+
+// cacheBase holds the options and counters shared by the caches.
+type cacheBase[K comparable, V any] struct {
+	capacity int
+	opts     CacheOptions[K, V]
+	stats    CacheStats
+}
+
+func newCacheBase[K comparable, V any](capacity int, opts *CacheOptions[K, V]) cacheBase[K, V] {
+	c := cacheBase[K, V]{capacity: capacity}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.Now == nil {
+		c.opts.Now = time.Now
+	}
+	return c
+}
+
+// expiry returns the expiration time for an element set now.
+func (c *cacheBase[K, V]) expiry() time.Time {
+	if c.opts.TTL <= 0 {
+		return time.Time{}
+	}
+	return c.opts.Now().Add(c.opts.TTL)
+}
+
+func (c *cacheBase[K, V]) expired(expires time.Time) bool {
+	return !expires.IsZero() && !c.opts.Now().Before(expires)
+}
+
+func (c *cacheBase[K, V]) evicted(k K, v V) {
+	c.stats.Evictions++
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(k, v)
+	}
+}
+
+// full checks if the cache with n elements has to evict one before adding a
+// new element.
+func (c *cacheBase[K, V]) full(n int) bool {
+	return c.capacity > 0 && n >= c.capacity
+}
+
+// Capacity returns the maximum number of elements in the cache, zero means
+// unbounded.
+func (c *cacheBase[K, V]) Capacity() int {
+	return max(c.capacity, 0)
+}
+
+// Stats returns the hit, miss and eviction counters of the cache.
+func (c *cacheBase[K, V]) Stats() CacheStats {
+	return c.stats
+}
+
+// ResetStats sets all the counters of the cache to zero.
+func (c *cacheBase[K, V]) ResetStats() {
+	c.stats = CacheStats{}
+}
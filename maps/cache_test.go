@@ -0,0 +1,164 @@
+package maps
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// fakeClock is a manually advanced clock for testing TTLs.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func pairKeys[K comparable, V any](pairs []*Pair[K, V]) []K {
+	r := make([]K, len(pairs))
+	for i, p := range pairs {
+		r[i] = p.Key
+	}
+	return r
+}
+
+func TestLRU(t *testing.T) {
+	var evicted []string
+	c := NewLRU(3, &CacheOptions[string, int]{
+		OnEvict: func(k string, v int) { evicted = append(evicted, k) },
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")     // a is now the most recently used
+	c.Set("d", 4)  // evicts b
+	c.Set("c", 30) // update, no eviction
+	c.Get("x")     // miss
+	c.Peek("a")    // no effect on recency and stats
+
+	if got, want := pairKeys(c.Pairs()), []string{"c", "d", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Pairs() = %v, want %v", got, want)
+	}
+	if !slices.Equal(evicted, []string{"b"}) {
+		t.Errorf("evicted %v", evicted)
+	}
+	if v, ok := c.Get("c"); !ok || v != 30 {
+		t.Errorf("Get() = %d, %v", v, ok)
+	}
+	if got, want := c.Stats(), (CacheStats{Hits: 2, Misses: 1, Evictions: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if !c.Delete("a") || c.Delete("a") || c.Len() != 2 || len(evicted) != 1 {
+		t.Errorf("Delete() is wrong")
+	}
+	c.ResetStats()
+	c.Clear()
+	if c.Len() != 0 || c.Stats() != (CacheStats{}) || c.Capacity() != 3 {
+		t.Errorf("Clear() or ResetStats() is wrong")
+	}
+}
+
+func TestLFU(t *testing.T) {
+	var evicted []string
+	c := NewLFU(3, &CacheOptions[string, int]{
+		OnEvict: func(k string, v int) { evicted = append(evicted, k) },
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Set("d", 4) // c has the lowest count
+	c.Set("e", 5) // d has the lowest count
+	c.Get("e")
+	c.Get("c") // miss
+
+	if !slices.Equal(evicted, []string{"c", "d"}) {
+		t.Errorf("evicted %v", evicted)
+	}
+	if got, want := pairKeys(c.Pairs()), []string{"e", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Pairs() = %v, want %v", got, want)
+	}
+	// a: 3 uses, e and b: 2 uses with e used more recently
+	if got, want := pairKeys(c.PairsByFrequency()), []string{"a", "e", "b"}; !slices.Equal(got, want) {
+		t.Errorf("PairsByFrequency() = %v, want %v", got, want)
+	}
+	if got, want := c.Stats(), (CacheStats{Hits: 4, Misses: 1, Evictions: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// deleting the only element with the lowest count
+	c.Delete("b")
+	c.Delete("e")
+	c.Set("f", 6)
+	c.Get("f")
+	c.Set("g", 7)
+	c.Set("h", 8) // g has the lowest count
+	if got, want := pairKeys(c.Pairs()), []string{"h", "f", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Pairs() = %v, want %v", got, want)
+	}
+	if got, want := pairKeys(c.PairsByFrequency()), []string{"a", "f", "h"}; !slices.Equal(got, want) {
+		t.Errorf("PairsByFrequency() = %v, want %v", got, want)
+	}
+	c.Clear()
+	c.Set("i", 9)
+	if c.Len() != 1 || c.buckets[1].Len() != 1 || c.recent.Len() != 1 {
+		t.Errorf("Clear() left stale state")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	type cache interface {
+		Set(k string, v int)
+		Get(k string) (int, bool)
+		Peek(k string) (int, bool)
+		Purge()
+		Len() int
+		Pairs() []*Pair[string, int]
+		Stats() CacheStats
+	}
+	for name, create := range map[string]func(*CacheOptions[string, int]) cache{
+		"LRU": func(o *CacheOptions[string, int]) cache { return NewLRU(0, o) },
+		"LFU": func(o *CacheOptions[string, int]) cache { return NewLFU(0, o) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			var evicted []string
+			c := create(&CacheOptions[string, int]{
+				TTL:     time.Minute,
+				Now:     clock.Now,
+				OnEvict: func(k string, v int) { evicted = append(evicted, k) },
+			})
+			c.Set("a", 1)
+			clock.Advance(30 * time.Second)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			clock.Advance(30 * time.Second) // a expires
+
+			if _, ok := c.Peek("a"); ok {
+				t.Errorf("Peek() returned an expired element")
+			}
+			if got := pairKeys(c.Pairs()); len(got) != 2 {
+				t.Errorf("Pairs() = %v", got)
+			}
+			if _, ok := c.Get("a"); ok {
+				t.Errorf("Get() returned an expired element")
+			}
+			c.Set("b", 20) // restarts the TTL of b
+			clock.Advance(45 * time.Second)
+			c.Purge() // c expires
+			if v, ok := c.Get("b"); !ok || v != 20 || c.Len() != 1 {
+				t.Errorf("Get() = %d, %v with %d elements", v, ok, c.Len())
+			}
+			if !slices.Equal(evicted, []string{"a", "c"}) {
+				t.Errorf("evicted %v", evicted)
+			}
+			if got, want := c.Stats(), (CacheStats{Hits: 1, Misses: 1, Evictions: 2}); got != want {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
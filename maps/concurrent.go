@@ -0,0 +1,199 @@
+package maps
+
+import (
+	"iter"
+	"sync"
+)
+
+// Concurrent is a map that is safe for concurrent use by multiple goroutines.
+// All operations, including the compound ones such as Compute and Merge, are
+// atomic. They are guarded by a single sync.RWMutex, so readers do not block
+// each other.
+//
+// The zero value is an empty map ready to use. A Concurrent must not be copied
+// after first use.
+type Concurrent[K comparable, V comparable] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewConcurrent returns a concurrent map populated with a copy of m.
+func NewConcurrent[M ~map[K]V, K comparable, V comparable](m M) *Concurrent[K, V] {
+	c := &Concurrent[K, V]{m: make(map[K]V, len(m))}
+	for k, v := range m {
+		c.m[k] = v
+	}
+	return c
+}
+
+func (c *Concurrent[K, V]) lazyInit() {
+	if c.m == nil {
+		c.m = map[K]V{}
+	}
+}
+
+// Len returns the number of elements in c.
+func (c *Concurrent[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.m)
+}
+
+// Load returns the value associated with the key k.
+func (c *Concurrent[K, V]) Load(k K) (v V, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok = c.m[k]
+	return
+}
+
+// Store associates the value v with the key k.
+func (c *Concurrent[K, V]) Store(k K, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+	c.m[k] = v
+}
+
+// LoadOrStore returns the existing value for the key k if present. Otherwise,
+// it stores and returns the value v. The loaded result is true if the value
+// was loaded, false if stored.
+func (c *Concurrent[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if actual, loaded = c.m[k]; loaded {
+		return
+	}
+	c.lazyInit()
+	c.m[k] = v
+	return v, false
+}
+
+// LoadAndDelete deletes the value for the key k, returning the previous value
+// if any.
+func (c *Concurrent[K, V]) LoadAndDelete(k K) (v V, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, loaded = c.m[k]; loaded {
+		delete(c.m, k)
+	}
+	return
+}
+
+// Delete removes the key k from c.
+func (c *Concurrent[K, V]) Delete(k K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.m, k)
+}
+
+// Swap stores the value v for the key k and returns the previous value if any.
+func (c *Concurrent[K, V]) Swap(k K, v V) (previous V, loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous, loaded = c.m[k]
+	c.lazyInit()
+	c.m[k] = v
+	return
+}
+
+// CompareAndSwap stores the value new for the key k if the existing value is
+// equal to old.
+func (c *Concurrent[K, V]) CompareAndSwap(k K, old, new V) (swapped bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.m[k]; !ok || v != old {
+		return false
+	}
+	c.m[k] = new
+	return true
+}
+
+// CompareAndDelete deletes the key k if its value is equal to old.
+func (c *Concurrent[K, V]) CompareAndDelete(k K, old V) (deleted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.m[k]; !ok || v != old {
+		return false
+	}
+	delete(c.m, k)
+	return true
+}
+
+// Compute atomically updates the value for the key k. The fn function
+// receives the current value and whether it exists, and returns the new value
+// and whether it should be kept; when keep is false, the key is deleted. fn is
+// called while c is locked, it must not access c.
+func (c *Concurrent[K, V]) Compute(k K, fn func(old V, loaded bool) (new V, keep bool)) (v V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, loaded := c.m[k]
+	v, ok = fn(old, loaded)
+	if ok {
+		c.lazyInit()
+		c.m[k] = v
+	} else if loaded {
+		delete(c.m, k)
+	}
+	return
+}
+
+// Merge atomically merges src into c, with the same semantics as the Merge
+// function: when a key from src is already in c and the associated values are
+// different, instead of overwriting, the key/value pair from src is copied to
+// conflicts.
+func (c *Concurrent[K, V]) Merge(src map[K]V) (conflicts map[K]V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+	return Merge(c.m, src)
+}
+
+// Insert atomically copies key-value pairs into c, with the same semantics as
+// the Insert function.
+func (c *Concurrent[K, V]) Insert(pairs ...*Pair[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+	Insert(c.m, pairs...)
+}
+
+// InsertOrOverwrite atomically copies key-value pairs into c, with the same
+// semantics as the InsertOrOverwrite function.
+func (c *Concurrent[K, V]) InsertOrOverwrite(pairs ...*Pair[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lazyInit()
+	InsertOrOverwrite(c.m, pairs...)
+}
+
+// Clear removes all elements from c.
+func (c *Concurrent[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.m)
+}
+
+// Snapshot returns a copy of c as a plain map that can be used with the rest
+// of this package.
+func (c *Concurrent[K, V]) Snapshot() map[K]V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r := make(map[K]V, len(c.m))
+	for k, v := range c.m {
+		r[k] = v
+	}
+	return r
+}
+
+// All returns an iterator over key-value pairs of a snapshot of c, taken when
+// the iteration starts. The loop body may safely access c.
+func (c *Concurrent[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range c.Snapshot() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package maps
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+// The tests below are meant to be run with the race detector as well:
+//
+//	go test -race ./maps
+
+func TestConcurrentAPI(t *testing.T) {
+	c := NewConcurrent(map[string]int{"a": 1})
+
+	if v, loaded := c.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Errorf("LoadOrStore(a) = %d, %v, want 1, true", v, loaded)
+	}
+	if v, loaded := c.LoadOrStore("b", 2); loaded || v != 2 {
+		t.Errorf("LoadOrStore(b) = %d, %v, want 2, false", v, loaded)
+	}
+	if c.CompareAndSwap("a", 2, 3) {
+		t.Errorf("CompareAndSwap() with a stale value succeeded")
+	}
+	if !c.CompareAndSwap("a", 1, 3) {
+		t.Errorf("CompareAndSwap() failed")
+	}
+	if c.CompareAndDelete("b", 1) {
+		t.Errorf("CompareAndDelete() with a stale value succeeded")
+	}
+	if !c.CompareAndDelete("b", 2) {
+		t.Errorf("CompareAndDelete() failed")
+	}
+	if prev, loaded := c.Swap("a", 4); !loaded || prev != 3 {
+		t.Errorf("Swap() = %d, %v, want 3, true", prev, loaded)
+	}
+
+	c.Compute("c", func(old int, loaded bool) (int, bool) {
+		return old + 10, true
+	})
+	c.Compute("a", func(old int, loaded bool) (int, bool) {
+		return 0, false
+	})
+	if want := map[string]int{"c": 10}; !maps.Equal(c.Snapshot(), want) {
+		t.Errorf("Snapshot() = %v, want %v", c.Snapshot(), want)
+	}
+
+	conflicts := c.Merge(map[string]int{"c": 11, "d": 12})
+	if want := map[string]int{"c": 11}; !maps.Equal(conflicts, want) {
+		t.Errorf("Merge() conflicts = %v, want %v", conflicts, want)
+	}
+	if want := map[string]int{"c": 10, "d": 12}; !maps.Equal(c.Snapshot(), want) {
+		t.Errorf("Merge() = %v, want %v", c.Snapshot(), want)
+	}
+
+	if v, loaded := c.LoadAndDelete("d"); !loaded || v != 12 || c.Len() != 1 {
+		t.Errorf("LoadAndDelete() = %d, %v, len %d", v, loaded, c.Len())
+	}
+}
+
+func TestConcurrentZeroValue(t *testing.T) {
+	var c Concurrent[int, int]
+	if _, ok := c.Load(1); ok || c.Len() != 0 {
+		t.Errorf("zero value is not empty")
+	}
+	c.Delete(1)
+	c.Clear()
+	c.Store(1, 1)
+	if v, ok := c.Load(1); !ok || v != 1 {
+		t.Errorf("Load() = %d, %v, want 1, true", v, ok)
+	}
+}
+
+func TestConcurrentParallelCompute(t *testing.T) {
+	const workers, iterations = 8, 1000
+	var c Concurrent[string, int]
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				c.Compute("counter", func(old int, loaded bool) (int, bool) {
+					return old + 1, true
+				})
+				c.Load("counter")
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := c.Load("counter"); v != workers*iterations {
+		t.Errorf("counter = %d, want %d", v, workers*iterations)
+	}
+}
+
+func TestConcurrentParallelMerge(t *testing.T) {
+	const workers, keys = 8, 100
+	var c Concurrent[int, int]
+	var wg sync.WaitGroup
+	conflicts := make([]map[int]int, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			src := map[int]int{}
+			for k := 0; k < keys; k++ {
+				src[k] = w
+			}
+			conflicts[w] = c.Merge(src)
+			for range c.All() {
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every key is won by exactly one worker, all other workers see a conflict
+	total := 0
+	for _, cf := range conflicts {
+		total += len(cf)
+	}
+	if want := keys * (workers - 1); total != want {
+		t.Errorf("total conflicts = %d, want %d", total, want)
+	}
+	if c.Len() != keys {
+		t.Errorf("Len() = %d, want %d", c.Len(), keys)
+	}
+}
+
+func TestConcurrentParallelCompareAndSwap(t *testing.T) {
+	const workers, iterations = 8, 500
+	var c Concurrent[string, int]
+	c.Store("x", 0)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; {
+				v, _ := c.Load("x")
+				if c.CompareAndSwap("x", v, v+1) {
+					i++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := c.Load("x"); v != workers*iterations {
+		t.Errorf("x = %d, want %d", v, workers*iterations)
+	}
+}
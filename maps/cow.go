@@ -0,0 +1,76 @@
+package maps
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWrite is a map for read-mostly workloads that is safe for concurrent
+// use. Readers never lock: Load returns the current version of the map with a
+// single atomic operation. Writers are serialized; each Update works on a
+// private copy which is then published atomically, so readers observe either
+// all or none of the changes made by an Update.
+//
+// The zero value is an empty map ready to use. A CopyOnWrite must not be
+// copied after first use.
+type CopyOnWrite[K comparable, V any] struct {
+	mu sync.Mutex // serializes writers
+	p  atomic.Pointer[map[K]V]
+}
+
+// NewCopyOnWrite returns a copy-on-write map populated with a copy of m.
+func NewCopyOnWrite[M ~map[K]V, K comparable, V any](m M) *CopyOnWrite[K, V] {
+	c := &CopyOnWrite[K, V]{}
+	r := make(map[K]V, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	c.p.Store(&r)
+	return c
+}
+
+// Load returns the current version of the map. The result is a read-only
+// view: it can be used with all the functions of this package that do not
+// modify their arguments, but it must not be modified itself.
+func (c *CopyOnWrite[K, V]) Load() map[K]V {
+	if p := c.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Get returns the value associated with the key k in the current version of
+// the map.
+func (c *CopyOnWrite[K, V]) Get(k K) (v V, ok bool) {
+	v, ok = c.Load()[k]
+	return
+}
+
+// Len returns the number of elements in the current version of the map.
+func (c *CopyOnWrite[K, V]) Len() int {
+	return len(c.Load())
+}
+
+// Update calls fn with a private copy of the current map and then publishes
+// the copy as the new version. Batching several changes in one Update makes
+// them visible to readers at once. Updates are serialized, fn must not call
+// Update or Store on c.
+func (c *CopyOnWrite[K, V]) Update(fn func(m map[K]V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cur := c.Load()
+	next := make(map[K]V, len(cur))
+	for k, v := range cur {
+		next[k] = v
+	}
+	fn(next)
+	c.p.Store(&next)
+}
+
+// Store publishes m as the new version of the map. c takes the ownership of
+// m: it must not be modified after the call.
+func (c *CopyOnWrite[K, V]) Store(m map[K]V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.Store(&m)
+}
@@ -0,0 +1,80 @@
+package maps
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestCopyOnWrite(t *testing.T) {
+	var c CopyOnWrite[string, int]
+	if c.Len() != 0 || c.Load() != nil {
+		t.Fatalf("zero value is not empty")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get() = _, true for an empty map")
+	}
+
+	c.Update(func(m map[string]int) {
+		m["a"] = 1
+		m["b"] = 2
+	})
+	before := c.Load()
+	c.Update(func(m map[string]int) {
+		delete(m, "a")
+		m["c"] = 3
+	})
+
+	if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(before, want) {
+		t.Errorf("Update() modified a published version: %v", before)
+	}
+	if got, want := c.Load(), map[string]int{"b": 2, "c": 3}; !maps.Equal(got, want) {
+		t.Errorf("Load() = %v, want %v", got, want)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Errorf("Get() = %d, %v, want 3, true", v, ok)
+	}
+
+	c.Store(map[string]int{"d": 4})
+	if c.Len() != 1 || !maps.Equal(before, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Store() = %v", c.Load())
+	}
+
+	src := map[string]int{"x": 1}
+	n := NewCopyOnWrite(src)
+	src["y"] = 2
+	if n.Len() != 1 {
+		t.Errorf("NewCopyOnWrite() did not copy the map")
+	}
+}
+
+func TestCopyOnWriteParallel(t *testing.T) {
+	c := NewCopyOnWrite(map[string]int{"a": 0, "b": 0, "c": 0})
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// every Update sets all the values to the same number
+				m := c.Load()
+				if len(m) != 3 || m["a"] != m["b"] || m["b"] != m["c"] {
+					t.Errorf("partially applied update: %v", m)
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 100; i++ {
+		c.Update(func(m map[string]int) {
+			for k := range m {
+				m[k] = i
+			}
+		})
+	}
+	wg.Wait()
+	if got, want := c.Load(), map[string]int{"a": 100, "b": 100, "c": 100}; !maps.Equal(got, want) {
+		t.Errorf("Load() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package maps
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SliceStrategy determines how DeepMerge combines two slices found at the same
+// path.
+type SliceStrategy int
+
+const (
+	// SliceConflict treats slices as any other values: differing slices are
+	// reported as conflicts.
+	SliceConflict SliceStrategy = iota
+	// SliceReplace replaces the dst slice with the src slice.
+	SliceReplace
+	// SliceAppend appends the src elements to the dst slice.
+	SliceAppend
+	// SliceUnion appends the src elements that are not already in the dst
+	// slice. Map elements that have the DeepMergeOptions.SliceKey field are
+	// matched by the value of that field and merged recursively, other
+	// elements are matched by equality.
+	SliceUnion
+)
+
+// DeepMergeOptions control the behavior of DeepMerge. The zero value mirrors
+// Merge: conflicting values are kept in dst and reported.
+type DeepMergeOptions struct {
+	Slices    SliceStrategy
+	SliceKey  string // identifies map elements for SliceUnion, e.g. "name"
+	Overwrite bool   // conflicting values from src overwrite dst, they are still reported
+}
+
+// DeepMerge recursively merges src into dst. The documents are expected to be
+// made of map[string]any, []any and scalar values, as produced by decoding
+// JSON or YAML.
+//
+//   - Nested maps present in both dst and src are merged recursively
+//   - Slices present in both dst and src are combined according to
+//     opts.Slices
+//   - Values from src are deep-copied into dst, dst never shares nested maps
+//     or slices with src
+//   - When a value from src is already in dst and the values are different,
+//     the value from src is copied to conflicts under its dotted path, e.g.
+//     "server.tls.cert", matched slice elements are addressed as
+//     "servers[name]"
+//
+// A nil opts is equivalent to the zero value of DeepMergeOptions.
+func DeepMerge(dst, src map[string]any, opts *DeepMergeOptions) (conflicts map[string]any) {
+	dm := deepMerger{conflicts: map[string]any{}}
+	if opts != nil {
+		dm.opts = *opts
+	}
+	dm.mergeMaps("", dst, src)
+	return dm.conflicts
+}
+
+type deepMerger struct {
+	opts      DeepMergeOptions
+	conflicts map[string]any
+}
+
+func (dm *deepMerger) mergeMaps(path string, dst, src map[string]any) {
+	for k, src_v := range src {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if dst_v, exists := dst[k]; exists {
+			dst[k] = dm.mergeValues(p, dst_v, src_v)
+		} else {
+			dst[k] = deepClone(src_v)
+		}
+	}
+}
+
+func (dm *deepMerger) mergeValues(path string, dst_v, src_v any) any {
+	switch d := dst_v.(type) {
+	case map[string]any:
+		if s, ok := src_v.(map[string]any); ok {
+			dm.mergeMaps(path, d, s)
+			return d
+		}
+	case []any:
+		if s, ok := src_v.([]any); ok && dm.opts.Slices != SliceConflict {
+			return dm.mergeSlices(path, d, s)
+		}
+	}
+	if reflect.DeepEqual(dst_v, src_v) {
+		return dst_v
+	}
+	dm.conflicts[path] = src_v
+	if dm.opts.Overwrite {
+		return deepClone(src_v)
+	}
+	return dst_v
+}
+
+func (dm *deepMerger) mergeSlices(path string, dst, src []any) []any {
+	switch dm.opts.Slices {
+	case SliceReplace:
+		return deepClone(src).([]any)
+	case SliceAppend:
+		r := make([]any, 0, len(dst)+len(src))
+		r = append(r, dst...)
+		return append(r, deepClone(src).([]any)...)
+	}
+
+	r := dst
+	by_key := map[any]int{}
+	for i, e := range r {
+		if id, ok := dm.sliceKey(e); ok {
+			by_key[id] = i
+		}
+	}
+	for _, e := range src {
+		if id, ok := dm.sliceKey(e); ok {
+			if i, found := by_key[id]; found {
+				r[i] = dm.mergeValues(fmt.Sprintf("%s[%v]", path, id), r[i], e)
+				continue
+			}
+			by_key[id] = len(r)
+		} else if containsDeepEqual(r, e) {
+			continue
+		}
+		r = append(r, deepClone(e))
+	}
+	return r
+}
+
+// sliceKey returns the value that identifies a map element for SliceUnion.
+func (dm *deepMerger) sliceKey(e any) (any, bool) {
+	m, ok := e.(map[string]any)
+	if !ok || dm.opts.SliceKey == "" {
+		return nil, false
+	}
+	id, ok := m[dm.opts.SliceKey]
+	if !ok || id == nil || !reflect.TypeOf(id).Comparable() {
+		return nil, false
+	}
+	return id, true
+}
+
+func containsDeepEqual(s []any, e any) bool {
+	for _, v := range s {
+		if reflect.DeepEqual(v, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func deepClone(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		r := make(map[string]any, len(t))
+		for k, e := range t {
+			r[k] = deepClone(e)
+		}
+		return r
+	case []any:
+		r := make([]any, len(t))
+		for i, e := range t {
+			r[i] = deepClone(e)
+		}
+		return r
+	default:
+		return v
+	}
+}
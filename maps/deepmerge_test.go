@@ -0,0 +1,114 @@
+package maps
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func doc(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		dst, src  string
+		opts      *DeepMergeOptions
+		want      string
+		conflicts string
+	}{
+		{
+			"nested",
+			`{"server":{"host":"a","tls":{"cert":"x"}}}`,
+			`{"server":{"port":80,"tls":{"cert":"y","key":"k"}}}`,
+			nil,
+			`{"server":{"host":"a","port":80,"tls":{"cert":"x","key":"k"}}}`,
+			`{"server.tls.cert":"y"}`,
+		},
+		{
+			"overwrite",
+			`{"server":{"tls":{"cert":"x"}}}`,
+			`{"server":{"tls":{"cert":"y"}}}`,
+			&DeepMergeOptions{Overwrite: true},
+			`{"server":{"tls":{"cert":"y"}}}`,
+			`{"server.tls.cert":"y"}`,
+		},
+		{
+			"type mismatch",
+			`{"a":{"b":1}}`,
+			`{"a":[1]}`,
+			nil,
+			`{"a":{"b":1}}`,
+			`{"a":[1]}`,
+		},
+		{
+			"slice conflict",
+			`{"a":[1,2],"b":[3]}`,
+			`{"a":[2,3],"b":[3]}`,
+			nil,
+			`{"a":[1,2],"b":[3]}`,
+			`{"a":[2,3]}`,
+		},
+		{
+			"slice replace",
+			`{"a":[1,2]}`,
+			`{"a":[2,3]}`,
+			&DeepMergeOptions{Slices: SliceReplace},
+			`{"a":[2,3]}`,
+			`{}`,
+		},
+		{
+			"slice append",
+			`{"a":[1,2]}`,
+			`{"a":[2,3]}`,
+			&DeepMergeOptions{Slices: SliceAppend},
+			`{"a":[1,2,2,3]}`,
+			`{}`,
+		},
+		{
+			"slice union",
+			`{"a":[1,2]}`,
+			`{"a":[2,3]}`,
+			&DeepMergeOptions{Slices: SliceUnion},
+			`{"a":[1,2,3]}`,
+			`{}`,
+		},
+		{
+			"slice union by key",
+			`{"servers":[{"name":"a","port":1},{"name":"b","port":2}]}`,
+			`{"servers":[{"name":"b","port":3,"tls":true},{"name":"c","port":4}]}`,
+			&DeepMergeOptions{Slices: SliceUnion, SliceKey: "name"},
+			`{"servers":[{"name":"a","port":1},{"name":"b","port":2,"tls":true},{"name":"c","port":4}]}`,
+			`{"servers[b].port":3}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := doc(t, tt.dst)
+			conflicts := DeepMerge(dst, doc(t, tt.src), tt.opts)
+			if want := doc(t, tt.want); !reflect.DeepEqual(dst, want) {
+				t.Errorf("DeepMerge() = %v, want %v", dst, want)
+			}
+			if want := doc(t, tt.conflicts); !reflect.DeepEqual(conflicts, want) {
+				t.Errorf("conflicts = %v, want %v", conflicts, want)
+			}
+		})
+	}
+}
+
+func TestDeepMergeDoesNotAlias(t *testing.T) {
+	dst := map[string]any{}
+	src := doc(t, `{"a":{"b":1},"c":[1]}`)
+	DeepMerge(dst, src, nil)
+	DeepMerge(dst, doc(t, `{"a":{"x":2}}`), nil)
+	dst["c"].([]any)[0] = 42.0
+	if want := doc(t, `{"a":{"b":1},"c":[1]}`); !reflect.DeepEqual(src, want) {
+		t.Errorf("src was modified: %v", src)
+	}
+}
@@ -0,0 +1,99 @@
+package maps
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Change holds the old and the new value of a key.
+type Change[V any] struct {
+	Old V
+	New V
+}
+
+// DiffResult describes the differences between two maps, as calculated by
+// Diff and DiffFunc.
+type DiffResult[K comparable, V any] struct {
+	Added     map[K]V         // keys that exist only in the new map
+	Removed   map[K]V         // keys that exist only in the old map
+	Changed   map[K]Change[V] // keys that exist in both maps with different values
+	Unchanged map[K]struct{}  // keys that exist in both maps with equal values
+}
+
+// Diff calculates the differences between maps a (old) and b (new).
+//
+//   - Data in both a and b remains unchanged
+//   - Unlike CalcMerge, keys that are in a but not in b are reported as well
+func Diff[M1 ~map[K]V, M2 ~map[K]V, K comparable, V comparable](a M1, b M2) *DiffResult[K, V] {
+	return DiffFunc(a, b, func(a, b V) bool {
+		return a == b
+	})
+}
+
+// DiffFunc provides the same functionality as Diff, but uses the equal functor
+// to compare values, which allows comparing non-comparable values.
+func DiffFunc[M1 ~map[K]V, M2 ~map[K]V, K comparable, V any](a M1, b M2, equal func(a, b V) bool) *DiffResult[K, V] {
+	d := &DiffResult[K, V]{
+		Added:     map[K]V{},
+		Removed:   map[K]V{},
+		Changed:   map[K]Change[V]{},
+		Unchanged: map[K]struct{}{},
+	}
+	for k, old_v := range a {
+		new_v, exists := b[k]
+		if !exists {
+			d.Removed[k] = old_v
+		} else if equal(old_v, new_v) {
+			d.Unchanged[k] = struct{}{}
+		} else {
+			d.Changed[k] = Change[V]{old_v, new_v}
+		}
+	}
+	for k, new_v := range b {
+		if _, exists := a[k]; !exists {
+			d.Added[k] = new_v
+		}
+	}
+	return d
+}
+
+// Empty reports whether there are no differences.
+func (d *DiffResult[K, V]) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders a human-readable summary of the differences, one key per
+// line, sorted by the formatted key. Added keys are prefixed with '+', removed
+// keys with '-' and changed keys with '~' followed by "old -> new". Unchanged
+// keys are not included.
+func (d *DiffResult[K, V]) String() string {
+	type line struct {
+		key, text string
+	}
+	lines := make([]line, 0, len(d.Added)+len(d.Removed)+len(d.Changed))
+	for k, v := range d.Added {
+		key := fmt.Sprint(k)
+		lines = append(lines, line{key, fmt.Sprintf("+ %s: %v", key, v)})
+	}
+	for k, v := range d.Removed {
+		key := fmt.Sprint(k)
+		lines = append(lines, line{key, fmt.Sprintf("- %s: %v", key, v)})
+	}
+	for k, c := range d.Changed {
+		key := fmt.Sprint(k)
+		lines = append(lines, line{key, fmt.Sprintf("~ %s: %v -> %v", key, c.Old, c.New)})
+	}
+	slices.SortFunc(lines, func(a, b line) bool {
+		return a.key < b.key
+	})
+	var sb strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(l.text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package maps
+
+import (
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      map[int]string
+		added     map[int]string
+		removed   map[int]string
+		changed   map[int]Change[string]
+		unchanged map[int]struct{}
+	}{
+		{"empty", nil, nil, map[int]string{}, map[int]string{}, map[int]Change[string]{}, map[int]struct{}{}},
+		{"added", nil, map[int]string{1: "a"}, map[int]string{1: "a"}, map[int]string{}, map[int]Change[string]{}, map[int]struct{}{}},
+		{"removed", map[int]string{1: "a"}, nil, map[int]string{}, map[int]string{1: "a"}, map[int]Change[string]{}, map[int]struct{}{}},
+		{"changed", map[int]string{1: "a"}, map[int]string{1: "b"}, map[int]string{}, map[int]string{}, map[int]Change[string]{1: {"a", "b"}}, map[int]struct{}{}},
+		{"unchanged", map[int]string{1: "a"}, map[int]string{1: "a"}, map[int]string{}, map[int]string{}, map[int]Change[string]{}, map[int]struct{}{1: {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diff(tt.a, tt.b)
+			if !maps.Equal(d.Added, tt.added) {
+				t.Errorf("Added = %v, want %v", d.Added, tt.added)
+			}
+			if !maps.Equal(d.Removed, tt.removed) {
+				t.Errorf("Removed = %v, want %v", d.Removed, tt.removed)
+			}
+			if !maps.Equal(d.Changed, tt.changed) {
+				t.Errorf("Changed = %v, want %v", d.Changed, tt.changed)
+			}
+			if !maps.Equal(d.Unchanged, tt.unchanged) {
+				t.Errorf("Unchanged = %v, want %v", d.Unchanged, tt.unchanged)
+			}
+			if want := len(tt.unchanged) == len(tt.a) && len(tt.a) == len(tt.b); d.Empty() != want {
+				t.Errorf("Empty() = %v, want %v", d.Empty(), want)
+			}
+		})
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	a := map[string][]int{"x": {1, 2}, "y": {3}}
+	b := map[string][]int{"x": {1, 2}, "y": {3, 4}}
+	d := DiffFunc(a, b, slices.Equal[int])
+	if len(d.Changed) != 1 || len(d.Unchanged) != 1 {
+		t.Errorf("DiffFunc() = %v", d)
+	}
+	if c := d.Changed["y"]; !slices.Equal(c.Old, []int{3}) || !slices.Equal(c.New, []int{3, 4}) {
+		t.Errorf("Changed[y] = %v", c)
+	}
+}
@@ -1,6 +1,8 @@
 package maps
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -193,3 +195,422 @@ func ExampleInverted() {
 	// DUPLICATES
 	// 42: fourty two, the answer to everything
 }
+
+func ExampleAllSortedByKey() {
+	m := map[int]string{
+		3: "three",
+		1: "one",
+		4: "four",
+		2: "two",
+	}
+
+	for k, v := range AllSortedByKey(m) {
+		fmt.Printf("%d: %s\n", k, v)
+	}
+	// Output:
+	// 1: one
+	// 2: two
+	// 3: three
+	// 4: four
+}
+
+func ExampleAllStableSortedByVal() {
+	m := map[int]string{
+		1: "B",
+		2: "A",
+		3: "B",
+		4: "A",
+	}
+
+	for k, v := range AllStableSortedByVal(m) {
+		fmt.Printf("%d: %s\n", k, v)
+	}
+	// Output:
+	// 2: A
+	// 4: A
+	// 1: B
+	// 3: B
+}
+
+func ExampleCollectPairs() {
+	m := map[string]int{
+		"one": 1,
+		"two": 2,
+	}
+
+	dst := map[string]int{
+		"one": 100,
+	}
+	Insert(dst, CollectPairs(All(m))...)
+	for k, v := range AllSortedByKey(dst) {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	// Output:
+	// one: 100
+	// two: 2
+}
+
+func ExampleOrderedMap() {
+	m := OrderedMap[string, string]{}
+	m.Set("Host", "example.com")
+	m.Set("Accept", "*/*")
+	m.Set("Content-Type", "text/plain")
+	m.Set("Accept", "text/html")
+	m.MoveToFront("Content-Type")
+
+	for k, v := range m.All() {
+		fmt.Printf("%s: %s\n", k, v)
+	}
+	// Output:
+	// Content-Type: text/plain
+	// Host: example.com
+	// Accept: text/html
+}
+
+func ExampleSortedMap() {
+	m := SortedMapFrom(map[int]string{
+		10: "ten",
+		20: "twenty",
+		30: "thirty",
+		40: "forty",
+	})
+	m.Set(25, "twenty five")
+
+	if k, v, ok := m.Floor(29); ok {
+		fmt.Printf("floor(29) = %d: %s\n", k, v)
+	}
+	if k, v, ok := m.Ceiling(31); ok {
+		fmt.Printf("ceiling(31) = %d: %s\n", k, v)
+	}
+	fmt.Printf("rank(30) = %d\n", m.Rank(30))
+	for k, v := range m.Range(20, 40) {
+		fmt.Printf("%d: %s\n", k, v)
+	}
+	// Output:
+	// floor(29) = 25: twenty five
+	// ceiling(31) = 40: forty
+	// rank(30) = 3
+	// 20: twenty
+	// 25: twenty five
+	// 30: thirty
+}
+
+func ExampleSortedMapFunc() {
+	m := NewSortedMapFunc[string, int](func(a, b string) bool {
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	m.Set("banana", 2)
+	m.Set("Apple", 1)
+	m.Set("cherry", 3)
+
+	for k, v := range m.Backward() {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	if k, _, ok := m.Select(0); ok {
+		fmt.Printf("first: %s\n", k)
+	}
+	// Output:
+	// cherry: 3
+	// banana: 2
+	// Apple: 1
+	// first: Apple
+}
+
+func ExampleBiMap() {
+	codes := BiMap[string, int]{}
+	codes.Insert("OK", 200)
+	codes.Insert("Not Found", 404)
+
+	if !codes.Insert("Found", 200) {
+		fmt.Println("200 is already taken")
+	}
+
+	byCode := codes.Inverse()
+	byCode.Set(500, "Internal Server Error")
+
+	if k, ok := codes.GetKey(404); ok {
+		fmt.Printf("404: %s\n", k)
+	}
+	for k, v := range AllSortedByKey(codes.Map()) {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	// Output:
+	// 200 is already taken
+	// 404: Not Found
+	// Internal Server Error: 500
+	// Not Found: 404
+	// OK: 200
+}
+
+func ExampleBiMapFrom() {
+	m := map[string]int{
+		"one":                      1,
+		"two":                      2,
+		"fourty two":               42,
+		"the answer to everything": 42,
+	}
+
+	b, duplicates := BiMapFrom(m)
+	for k, v := range AllSortedByKey(b.Map()) {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	for v := range duplicates {
+		fmt.Printf("duplicate: %d\n", v)
+	}
+	// Output:
+	// one: 1
+	// two: 2
+	// duplicate: 42
+}
+
+func ExampleInvertedMulti() {
+	m := map[string]int{
+		"one":                      1,
+		"two":                      2,
+		"fourty two":               42,
+		"the answer to everything": 42,
+	}
+
+	for v, keys := range AllSortedByKey(InvertedMulti(m)) {
+		fmt.Printf("%d: %s\n", v, strings.Join(sets.Sorted(keys), ", "))
+	}
+	// Output:
+	// 1: one
+	// 2: two
+	// 42: fourty two, the answer to everything
+}
+
+func ExampleMultiMap() {
+	tags := MultiMap[string, string]{}
+	tags.Add("main.go", "go", "source")
+	tags.Add("README.md", "docs")
+	tags.Add("doc.go", "go", "docs")
+	tags.Remove("README.md", "docs")
+
+	fmt.Printf("files: %d, tags: %d, associations: %d\n",
+		len(tags), len(tags.Values()), tags.Count())
+	for tag, files := range AllSortedByKey(tags.Inverted()) {
+		fmt.Printf("%s: %s\n", tag, strings.Join(sets.Sorted(files), ", "))
+	}
+	// Output:
+	// files: 2, tags: 3, associations: 4
+	// docs: doc.go
+	// go: doc.go, main.go
+	// source: main.go
+}
+
+func ExampleMerge3() {
+	defaults := map[string]string{
+		"theme":    "light",
+		"font":     "mono",
+		"language": "en",
+	}
+	user := map[string]string{
+		"theme":    "dark",
+		"font":     "mono",
+		"language": "en",
+	}
+	upstream := map[string]string{
+		"theme":    "light",
+		"font":     "sans",
+		"language": "en",
+		"tabs":     "4",
+	}
+
+	merged, report := Merge3(defaults, user, upstream)
+	for k, v := range AllSortedByKey(merged) {
+		fmt.Printf("%s: %s (%s)\n", k, v, report[k])
+	}
+	// Output:
+	// font: sans (theirs-only)
+	// language: en (unchanged)
+	// tabs: 4 (theirs-only)
+	// theme: dark (ours-only)
+}
+
+func ExampleDiff() {
+	before := map[string]int{
+		"workers": 4,
+		"timeout": 30,
+		"retries": 3,
+	}
+	after := map[string]int{
+		"workers": 8,
+		"timeout": 30,
+		"backlog": 128,
+	}
+
+	d := Diff(before, after)
+	fmt.Println(d)
+	fmt.Printf("unchanged: %d\n", len(d.Unchanged))
+	// Output:
+	// + backlog: 128
+	// - retries: 3
+	// ~ workers: 4 -> 8
+	// unchanged: 1
+}
+
+func ExamplePatch() {
+	settings := map[string]string{
+		"theme": "light",
+		"font":  "mono",
+	}
+
+	p := CalcPatch(settings, map[string]string{
+		"theme": "dark",
+		"font":  "mono",
+	})
+	data, _ := json.Marshal(p)
+	fmt.Println(string(data))
+
+	if err := p.Apply(settings); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(settings["theme"])
+
+	// applying the same patch again fails the optimistic check
+	if err := p.Apply(settings); err != nil {
+		fmt.Println(err)
+	}
+
+	if err := p.Invert().Apply(settings); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(settings["theme"])
+	// Output:
+	// [{"op":"replace","key":"theme","old":"light","value":"dark"}]
+	// dark
+	// maps: patch precondition failed: replace theme: have dark, want light
+	// light
+}
+
+func ExampleMergeWith() {
+	totals := map[string]int{
+		"apples":  3,
+		"bananas": 5,
+	}
+	counts := map[string]int{
+		"apples":   2,
+		"cherries": 7,
+	}
+
+	MergeWith(totals, counts, Sum)
+	for k, v := range AllSortedByKey(totals) {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	// Output:
+	// apples: 5
+	// bananas: 5
+	// cherries: 7
+}
+
+func ExampleMergeStrict() {
+	m := map[int]string{
+		1: "one",
+		2: "two",
+		3: "three",
+	}
+	m2 := map[int]string{
+		2: "TWO",
+		3: "THREE",
+		4: "four",
+	}
+
+	err := MergeStrict(m, m2)
+	fmt.Println(err)
+
+	var ce *ConflictError[int, string]
+	if errors.As(err, &ce) {
+		fmt.Printf("%d conflicts, m has %d keys\n", len(ce.Conflicts), len(m))
+	}
+	// Output:
+	// maps: 2 conflicting keys: 2 (two vs TWO), 3 (three vs THREE)
+	// 2 conflicts, m has 3 keys
+}
+
+func ExampleCopyOnWrite() {
+	flags := NewCopyOnWrite(map[string]bool{
+		"new-ui": false,
+	})
+
+	// readers never lock
+	if enabled, _ := flags.Get("new-ui"); !enabled {
+		fmt.Println("new-ui is disabled")
+	}
+
+	// writers batch their changes
+	flags.Update(func(m map[string]bool) {
+		InsertOrOverwrite(m, &Pair[string, bool]{"new-ui", true}, &Pair[string, bool]{"beta", true})
+	})
+
+	for k, v := range AllSortedByKey(flags.Load()) {
+		fmt.Printf("%s: %v\n", k, v)
+	}
+	// Output:
+	// new-ui is disabled
+	// beta: true
+	// new-ui: true
+}
+
+func ExampleImmutableMap() {
+	v1 := ImmutableMapFrom(map[string]int{"a": 1, "b": 2})
+	v2 := v1.With("c", 3).Without("a")
+
+	// v1 is not affected by the changes
+	fmt.Println(v1.Len(), v2.Len())
+
+	for k, v := range AllSortedByKey(v1.Union(v2).Map()) {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	for k := range v2.Difference(v1).All() {
+		fmt.Println("added:", k)
+	}
+	// Output:
+	// 2 2
+	// a: 1
+	// b: 2
+	// c: 3
+	// added: c
+}
+
+func ExampleTrieMap() {
+	routes := TrieMapFrom(map[string]string{
+		"/":            "root",
+		"/api/":        "api",
+		"/api/users/":  "users",
+		"/api/orders/": "orders",
+		"/static/":     "files",
+	})
+
+	_, handler, _ := routes.LongestPrefix("/api/users/42")
+	fmt.Println(handler)
+
+	for k, v := range routes.WithPrefix("/api/") {
+		fmt.Printf("%s: %s\n", k, v)
+	}
+	// Output:
+	// users
+	// /api/: api
+	// /api/orders/: orders
+	// /api/users/: users
+}
+
+func ExampleLRU() {
+	c := NewLRU(2, &CacheOptions[string, int]{
+		OnEvict: func(k string, v int) { fmt.Println("evicted", k) },
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	for _, p := range c.Pairs() {
+		fmt.Printf("%s: %d\n", p.Key, p.Val)
+	}
+	fmt.Printf("%+v\n", c.Stats())
+	// Output:
+	// evicted b
+	// c: 3
+	// a: 1
+	// {Hits:1 Misses:0 Evictions:1}
+}
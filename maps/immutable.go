@@ -0,0 +1,87 @@
+package maps
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/hamt"
+)
+
+// ImmutableMap is a persistent map: it is never modified in place. With and
+// Without return new versions in O(log n) that share most of their structure
+// with the original, so keeping old versions around is cheap. ImmutableMap
+// values are safe for concurrent use without locking.
+//
+// Union, Difference and Intersection skip the subtrees that are shared by
+// their operands, which makes them fast for versions derived from a common
+// ancestor.
+//
+// The zero value is an empty map ready to use.
+type ImmutableMap[K comparable, V any] struct {
+	m hamt.Map[K, V]
+}
+
+// ImmutableMapFrom returns an immutable map with the elements of m.
+func ImmutableMapFrom[M ~map[K]V, K comparable, V any](m M) ImmutableMap[K, V] {
+	var r hamt.Map[K, V]
+	for k, v := range m {
+		r = r.With(k, v)
+	}
+	return ImmutableMap[K, V]{r}
+}
+
+// Len returns the number of elements in m.
+func (m ImmutableMap[K, V]) Len() int {
+	return m.m.Len()
+}
+
+// Get returns the value associated with the key k.
+func (m ImmutableMap[K, V]) Get(k K) (v V, ok bool) {
+	return m.m.Get(k)
+}
+
+// Has checks if there is a key k in m.
+func (m ImmutableMap[K, V]) Has(k K) bool {
+	_, ok := m.m.Get(k)
+	return ok
+}
+
+// With returns a version of m with the value v associated with the key k.
+func (m ImmutableMap[K, V]) With(k K, v V) ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{m.m.With(k, v)}
+}
+
+// Without returns a version of m without the key k.
+func (m ImmutableMap[K, V]) Without(k K) ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{m.m.Without(k)}
+}
+
+// Union returns a map with the elements of both m and o. For keys that are in
+// both maps, the values from o are used.
+func (m ImmutableMap[K, V]) Union(o ImmutableMap[K, V]) ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{m.m.Union(o.m)}
+}
+
+// Difference returns a map with the elements of m whose keys are not in o.
+func (m ImmutableMap[K, V]) Difference(o ImmutableMap[K, V]) ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{m.m.Difference(o.m)}
+}
+
+// Intersection returns a map with the elements of m whose keys are also in o.
+func (m ImmutableMap[K, V]) Intersection(o ImmutableMap[K, V]) ImmutableMap[K, V] {
+	return ImmutableMap[K, V]{m.m.Intersection(o.m)}
+}
+
+// All returns an iterator over key-value pairs of m. The iteration order is
+// indeterminate.
+func (m ImmutableMap[K, V]) All() iter.Seq2[K, V] {
+	return m.m.All()
+}
+
+// Map returns the elements of m as a plain map.
+func (m ImmutableMap[K, V]) Map() map[K]V {
+	r := make(map[K]V, m.m.Len())
+	for k, v := range m.m.All() {
+		r[k] = v
+	}
+	return r
+}
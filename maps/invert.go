@@ -23,7 +23,8 @@ func HasDuplicates[M ~map[K]V, K comparable, V comparable](m M) bool {
 // A strategy for resolving the issues with duplicates then may include
 // iterating over the returned set of duplicates, possibly calling the
 // MatchValue function to discover which keys are associated to each duplicate
-// and taking appropriate actions.
+// and taking appropriate actions. When all the keys of duplicate values are
+// needed, InvertedMulti produces them in a single pass.
 //
 func Inverted[M ~map[K]V, K comparable, V comparable](m M) (inverted map[V]K, duplicates map[V]struct{}) {
 	inverted = map[V]K{}
@@ -52,3 +53,19 @@ func MatchValue[M ~map[K]V, K comparable, V comparable](m M, v V) (keys map[K]st
 	}
 	return
 }
+
+// InvertedMulti produces a one-to-many inverted map from m in a single pass.
+// Unlike Inverted, duplicates are not discarded: each value is mapped to the
+// set of all the keys that are associated with it in m.
+func InvertedMulti[M ~map[K]V, K comparable, V comparable](m M) map[V]map[K]struct{} {
+	r := map[V]map[K]struct{}{}
+	for k, v := range m {
+		keys, exists := r[v]
+		if !exists {
+			keys = map[K]struct{}{}
+			r[v] = keys
+		}
+		keys[k] = struct{}{}
+	}
+	return r
+}
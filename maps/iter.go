@@ -0,0 +1,158 @@
+package maps
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// All returns an iterator over key-value pairs in m. The pairs will be in an
+// indeterminate order. Unlike Pairs, no intermediate slice is allocated.
+func All[M ~map[K]V, K comparable, V any](m M) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// AllSortedFunc returns an iterator over key-value pairs in m sorted as
+// determined by the less function. It is the iterator counterpart of
+// SortedFunc.
+func AllSortedFunc[M ~map[K]V, K comparable, V any](m M, less func(a, b *Pair[K, V]) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldPairs(sortedPairs(m, less), yield)
+	}
+}
+
+// AllSortedByKey returns an iterator over key-value pairs in m sorted by key.
+// It is the iterator counterpart of SortedByKey, only the keys are collected
+// and sorted, values are looked up as the iteration progresses.
+func AllSortedByKey[M ~map[K]V, K constraints.Ordered, V any](m M) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys := maps.Keys(m)
+		slices.Sort(keys)
+		yieldKeys(m, keys, yield)
+	}
+}
+
+// AllSortedByKeyFunc returns an iterator over key-value pairs in m sorted by
+// key as determined by the less function. It is the iterator counterpart of
+// SortedByKeyFunc.
+func AllSortedByKeyFunc[M ~map[K]V, K comparable, V any](m M, less func(a, b K) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys := maps.Keys(m)
+		slices.SortFunc(keys, less)
+		yieldKeys(m, keys, yield)
+	}
+}
+
+// AllSortedByVal returns an iterator over key-value pairs in m sorted by
+// value. It is the iterator counterpart of SortedByVal.
+func AllSortedByVal[M ~map[K]V, K comparable, V constraints.Ordered](m M) iter.Seq2[K, V] {
+	return AllSortedFunc(m, lessByVal[K, V])
+}
+
+// AllSortedByValFunc returns an iterator over key-value pairs in m sorted by
+// value as determined by the less function. It is the iterator counterpart of
+// SortedByValFunc.
+func AllSortedByValFunc[M ~map[K]V, K comparable, V any](m M, less func(a, b V) bool) iter.Seq2[K, V] {
+	return AllSortedFunc(m, lessByValFunc[K](less))
+}
+
+// AllStableSortedByVal returns an iterator over key-value pairs in m sorted by
+// value, falling back to comparing keys for duplicate values. It is the
+// iterator counterpart of StableSortedByVal.
+func AllStableSortedByVal[M ~map[K]V, K constraints.Ordered, V constraints.Ordered](m M) iter.Seq2[K, V] {
+	return AllSortedFunc(m, lessByValKey[K, V])
+}
+
+// AllStableSortedByValFunc returns an iterator over key-value pairs in m
+// sorted by value as determined by the less function, falling back to
+// comparing keys for duplicate values. It is the iterator counterpart of
+// StableSortedByValFunc.
+func AllStableSortedByValFunc[M ~map[K]V, K constraints.Ordered, V any](m M, less func(a, b V) bool) iter.Seq2[K, V] {
+	return AllSortedFunc(m, lessByValKeyFunc[K](less))
+}
+
+// AllSortedKeys returns an iterator over sorted keys of the map m. It is the
+// iterator counterpart of SortedKeys.
+func AllSortedKeys[M ~map[K]V, K constraints.Ordered, V any](m M) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys := maps.Keys(m)
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// AllSortedKeysFunc returns an iterator over keys of the map m sorted as
+// determined by the less function. It is the iterator counterpart of
+// SortedKeysFunc.
+func AllSortedKeysFunc[M ~map[K]V, K comparable, V any](m M, less func(a, b K) bool) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys := maps.Keys(m)
+		slices.SortFunc(keys, less)
+		for _, k := range keys {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Collect collects key-value pairs from seq into a new map. When seq produces
+// duplicate keys, the last value wins.
+func Collect[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	r := map[K]V{}
+	for k, v := range seq {
+		r[k] = v
+	}
+	return r
+}
+
+// CollectPairs collects key-value pairs from seq into a slice, preserving the
+// order of iteration. The result can be passed to Insert and InsertOrOverwrite.
+func CollectPairs[K any, V any](seq iter.Seq2[K, V]) []*Pair[K, V] {
+	var r []*Pair[K, V]
+	for k, v := range seq {
+		r = append(r, &Pair[K, V]{k, v})
+	}
+	return r
+}
+
+// iteration helpers, used internally
+
+func sortedPairs[M ~map[K]V, K comparable, V any](m M, less func(a, b *Pair[K, V]) bool) []Pair[K, V] {
+	pairs := make([]Pair[K, V], 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, Pair[K, V]{k, v})
+	}
+	slices.SortFunc(pairs, func(a, b Pair[K, V]) bool {
+		return less(&a, &b)
+	})
+	return pairs
+}
+
+func yieldPairs[K any, V any](pairs []Pair[K, V], yield func(K, V) bool) {
+	for i := range pairs {
+		if !yield(pairs[i].Key, pairs[i].Val) {
+			return
+		}
+	}
+}
+
+func yieldKeys[M ~map[K]V, K comparable, V any](m M, keys []K, yield func(K, V) bool) {
+	for _, k := range keys {
+		if !yield(k, m[k]) {
+			return
+		}
+	}
+}
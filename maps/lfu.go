@@ -0,0 +1,197 @@
+package maps
+
+// LFU is a map with a bounded number of elements that evicts the least
+// frequently used element to make room for a new one. Get and Set count as a
+// use; among the elements with the same use count, the least recently used
+// one is evicted first. Elements can optionally expire after a TTL.
+//
+// All operations take O(1), except for evictions after a Delete, which may
+// take O(f) to find the lowest use count among f distinct ones. An LFU is not
+// safe for concurrent use.
+//
+// Use NewLFU to create an LFU.
+type LFU[K comparable, V any] struct {
+	cacheBase[K, V]
+	entries map[K]*lfuEntry[V]
+	buckets map[int]*OrderedMap[K, struct{}] // keys by use count, in recency order
+	recent  OrderedMap[K, struct{}]          // all keys in recency order
+	minFreq int
+}
+
+type lfuEntry[V any] struct {
+	cacheEntry[V]
+	freq int
+}
+
+// NewLFU returns an empty LFU cache that holds up to capacity elements. If
+// capacity <= 0, the cache is unbounded and elements are only removed when
+// they expire. The opts may be nil.
+func NewLFU[K comparable, V any](capacity int, opts *CacheOptions[K, V]) *LFU[K, V] {
+	return &LFU[K, V]{
+		cacheBase: newCacheBase(capacity, opts),
+		entries:   map[K]*lfuEntry[V]{},
+		buckets:   map[int]*OrderedMap[K, struct{}]{},
+	}
+}
+
+// Len returns the number of elements in c, including the expired elements
+// that have not been removed yet.
+func (c *LFU[K, V]) Len() int {
+	return len(c.entries)
+}
+
+// Get returns the value associated with the key k and counts it as a use.
+// Expired elements are evicted and reported as missing.
+func (c *LFU[K, V]) Get(k K) (v V, ok bool) {
+	e, ok := c.entries[k]
+	if ok && c.expired(e.expires) {
+		c.remove(k, e)
+		c.evicted(k, e.val)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return v, false
+	}
+	c.stats.Hits++
+	c.touch(k, e)
+	return e.val, true
+}
+
+// Peek returns the value associated with the key k, without counting it as a
+// use or updating the stats.
+func (c *LFU[K, V]) Peek(k K) (v V, ok bool) {
+	e, ok := c.entries[k]
+	if !ok || c.expired(e.expires) {
+		return v, false
+	}
+	return e.val, true
+}
+
+// Set associates the value v with the key k, counts it as a use and restarts
+// its TTL. If c is full, the least frequently used element is evicted.
+func (c *LFU[K, V]) Set(k K, v V) {
+	if e, ok := c.entries[k]; ok {
+		e.cacheEntry = cacheEntry[V]{v, c.expiry()}
+		c.touch(k, e)
+		return
+	}
+	for c.full(len(c.entries)) {
+		c.evict()
+	}
+	c.entries[k] = &lfuEntry[V]{cacheEntry[V]{v, c.expiry()}, 1}
+	c.bucket(1).Set(k, struct{}{})
+	c.recent.Set(k, struct{}{})
+	c.minFreq = 1
+}
+
+// Delete removes the key k from c. Returns false if there was no such key.
+func (c *LFU[K, V]) Delete(k K) bool {
+	e, ok := c.entries[k]
+	if ok {
+		c.remove(k, e)
+	}
+	return ok
+}
+
+// Clear removes all elements from c. The stats are preserved.
+func (c *LFU[K, V]) Clear() {
+	clear(c.entries)
+	clear(c.buckets)
+	c.recent.Clear()
+	c.minFreq = 0
+}
+
+// Purge evicts all the expired elements.
+func (c *LFU[K, V]) Purge() {
+	for k, e := range c.entries {
+		if c.expired(e.expires) {
+			c.remove(k, e)
+			c.evicted(k, e.val)
+		}
+	}
+}
+
+// Pairs returns a slice of the live key-value pairs in c in recency order,
+// from the most to the least recently used, the same way as LRU does. Use
+// PairsByFrequency to list them in eviction order.
+func (c *LFU[K, V]) Pairs() []*Pair[K, V] {
+	r := make([]*Pair[K, V], 0, len(c.entries))
+	for k := range c.recent.Backward() {
+		if e := c.entries[k]; !c.expired(e.expires) {
+			r = append(r, &Pair[K, V]{k, e.val})
+		}
+	}
+	return r
+}
+
+// PairsByFrequency returns a slice of the live key-value pairs in c, from the
+// most to the least frequently used. Elements with the same use count are in
+// recency order, from the most to the least recently used.
+func (c *LFU[K, V]) PairsByFrequency() []*Pair[K, V] {
+	freqs := SortedKeys(c.buckets)
+	r := make([]*Pair[K, V], 0, len(c.entries))
+	for i := len(freqs) - 1; i >= 0; i-- {
+		for k := range c.buckets[freqs[i]].Backward() {
+			if e := c.entries[k]; !c.expired(e.expires) {
+				r = append(r, &Pair[K, V]{k, e.val})
+			}
+		}
+	}
+	return r
+}
+
+func (c *LFU[K, V]) bucket(freq int) *OrderedMap[K, struct{}] {
+	b, ok := c.buckets[freq]
+	if !ok {
+		b = &OrderedMap[K, struct{}]{}
+		c.buckets[freq] = b
+	}
+	return b
+}
+
+// unbucket removes k from the bucket of its use count.
+func (c *LFU[K, V]) unbucket(k K, freq int) {
+	b := c.buckets[freq]
+	b.Delete(k)
+	if b.Len() == 0 {
+		delete(c.buckets, freq)
+	}
+}
+
+// touch counts a use of k.
+func (c *LFU[K, V]) touch(k K, e *lfuEntry[V]) {
+	c.unbucket(k, e.freq)
+	if c.minFreq == e.freq && c.buckets[e.freq] == nil {
+		c.minFreq++
+	}
+	e.freq++
+	c.bucket(e.freq).Set(k, struct{}{})
+	c.recent.MoveToBack(k)
+}
+
+func (c *LFU[K, V]) remove(k K, e *lfuEntry[V]) {
+	delete(c.entries, k)
+	c.unbucket(k, e.freq)
+	c.recent.Delete(k)
+}
+
+// evict removes the least recently used element among those with the lowest
+// use count.
+func (c *LFU[K, V]) evict() {
+	b, ok := c.buckets[c.minFreq]
+	if !ok {
+		// minFreq is stale after a removal, find the lowest use count
+		c.minFreq = 0
+		for f := range c.buckets {
+			if c.minFreq == 0 || f < c.minFreq {
+				c.minFreq = f
+			}
+		}
+		b = c.buckets[c.minFreq]
+	}
+	k, _, _ := b.Front()
+	e := c.entries[k]
+	c.remove(k, e)
+	c.evicted(k, e.val)
+}
@@ -0,0 +1,113 @@
+package maps
+
+import "time"
+
+// LRU is a map with a bounded number of elements that evicts the least
+// recently used element to make room for a new one. Get and Set mark an
+// element as used. Elements can optionally expire after a TTL.
+//
+// LRU is built on OrderedMap: the front holds the least recently used
+// element, the back holds the most recently used one. All operations take
+// O(1). An LRU is not safe for concurrent use.
+//
+// Use NewLRU to create an LRU.
+type LRU[K comparable, V any] struct {
+	cacheBase[K, V]
+	m OrderedMap[K, cacheEntry[V]]
+}
+
+type cacheEntry[V any] struct {
+	val     V
+	expires time.Time // zero if the element never expires
+}
+
+// NewLRU returns an empty LRU cache that holds up to capacity elements. If
+// capacity <= 0, the cache is unbounded and elements are only removed when
+// they expire. The opts may be nil.
+func NewLRU[K comparable, V any](capacity int, opts *CacheOptions[K, V]) *LRU[K, V] {
+	return &LRU[K, V]{cacheBase: newCacheBase(capacity, opts)}
+}
+
+// Len returns the number of elements in c, including the expired elements
+// that have not been removed yet.
+func (c *LRU[K, V]) Len() int {
+	return c.m.Len()
+}
+
+// Get returns the value associated with the key k and marks it as the most
+// recently used. Expired elements are evicted and reported as missing.
+func (c *LRU[K, V]) Get(k K) (v V, ok bool) {
+	e, ok := c.m.Get(k)
+	if ok && c.expired(e.expires) {
+		c.m.Delete(k)
+		c.evicted(k, e.val)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return v, false
+	}
+	c.stats.Hits++
+	c.m.MoveToBack(k)
+	return e.val, true
+}
+
+// Peek returns the value associated with the key k, without marking it as
+// used or updating the stats.
+func (c *LRU[K, V]) Peek(k K) (v V, ok bool) {
+	e, ok := c.m.Get(k)
+	if !ok || c.expired(e.expires) {
+		return v, false
+	}
+	return e.val, true
+}
+
+// Set associates the value v with the key k, marks it as the most recently
+// used and restarts its TTL. If c is full, the least recently used element is
+// evicted.
+func (c *LRU[K, V]) Set(k K, v V) {
+	e := cacheEntry[V]{v, c.expiry()}
+	if c.m.Has(k) {
+		c.m.Set(k, e)
+		c.m.MoveToBack(k)
+		return
+	}
+	for c.full(c.m.Len()) {
+		old_k, old_e, _ := c.m.Front()
+		c.m.Delete(old_k)
+		c.evicted(old_k, old_e.val)
+	}
+	c.m.Set(k, e)
+}
+
+// Delete removes the key k from c. Returns false if there was no such key.
+func (c *LRU[K, V]) Delete(k K) bool {
+	return c.m.Delete(k)
+}
+
+// Clear removes all elements from c. The stats are preserved.
+func (c *LRU[K, V]) Clear() {
+	c.m.Clear()
+}
+
+// Purge evicts all the expired elements.
+func (c *LRU[K, V]) Purge() {
+	for k, e := range c.m.All() {
+		if c.expired(e.expires) {
+			c.m.Delete(k)
+			c.evicted(k, e.val)
+		}
+	}
+}
+
+// Pairs returns a slice of the live key-value pairs in c in recency order,
+// from the most to the least recently used.
+func (c *LRU[K, V]) Pairs() []*Pair[K, V] {
+	r := make([]*Pair[K, V], 0, c.m.Len())
+	for k, e := range c.m.Backward() {
+		if !c.expired(e.expires) {
+			r = append(r, &Pair[K, V]{k, e.val})
+		}
+	}
+	return r
+}
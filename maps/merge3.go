@@ -0,0 +1,145 @@
+package maps
+
+// Merge3Status classifies a key in a three-way merge.
+type Merge3Status int
+
+const (
+	// Merge3Unchanged: the key has the same value in base, ours and theirs.
+	Merge3Unchanged Merge3Status = iota
+	// Merge3OursOnly: the key was added, modified or deleted in ours only.
+	Merge3OursOnly
+	// Merge3TheirsOnly: the key was added, modified or deleted in theirs only.
+	Merge3TheirsOnly
+	// Merge3BothSame: the key was changed the same way in ours and theirs.
+	Merge3BothSame
+	// Merge3Conflict: the key was modified in ours and theirs to different
+	// values.
+	Merge3Conflict
+	// Merge3DeletedOneSide: the key was deleted on one side and modified on
+	// the other.
+	Merge3DeletedOneSide
+)
+
+func (s Merge3Status) String() string {
+	switch s {
+	case Merge3Unchanged:
+		return "unchanged"
+	case Merge3OursOnly:
+		return "ours-only"
+	case Merge3TheirsOnly:
+		return "theirs-only"
+	case Merge3BothSame:
+		return "both-same"
+	case Merge3Conflict:
+		return "conflict"
+	case Merge3DeletedOneSide:
+		return "deleted-on-one-side"
+	default:
+		return "unknown"
+	}
+}
+
+// IsConflict reports whether s requires manual resolution.
+func (s Merge3Status) IsConflict() bool {
+	return s == Merge3Conflict || s == Merge3DeletedOneSide
+}
+
+// Merge3Report is a per-key classification produced by Merge3 and Merge3Func.
+type Merge3Report[K comparable] map[K]Merge3Status
+
+// Conflicts returns the keys that require manual resolution.
+func (r Merge3Report[K]) Conflicts() map[K]struct{} {
+	conflicts := map[K]struct{}{}
+	for k, s := range r {
+		if s.IsConflict() {
+			conflicts[k] = struct{}{}
+		}
+	}
+	return conflicts
+}
+
+// Keys returns the keys that are classified as s.
+func (r Merge3Report[K]) Keys(s Merge3Status) map[K]struct{} {
+	keys := map[K]struct{}{}
+	for k, ks := range r {
+		if ks == s {
+			keys[k] = struct{}{}
+		}
+	}
+	return keys
+}
+
+// Merge3 performs a three-way merge of ours and theirs, both derived from the
+// common ancestor base. Unlike Merge, it can tell whether a key was changed on
+// one side or on both sides.
+//
+//   - Data in base, ours and theirs remains unchanged
+//   - The merged map is returned along with the per-key classification report
+//   - Changes made on one side only, including deletions, are applied
+//   - Conflicts are resolved in favour of ours, the same way Merge keeps the
+//     dst values; the caller may inspect report.Conflicts() to take
+//     appropriate actions
+func Merge3[M ~map[K]V, K comparable, V comparable](base, ours, theirs M) (merged M, report Merge3Report[K]) {
+	return Merge3Func(base, ours, theirs, func(a, b V) bool {
+		return a == b
+	})
+}
+
+// Merge3Func provides the same functionality as Merge3, but uses the equal
+// functor to compare values, which allows merging non-comparable values.
+func Merge3Func[M ~map[K]V, K comparable, V any](base, ours, theirs M, equal func(a, b V) bool) (merged M, report Merge3Report[K]) {
+	merged = M{}
+	report = Merge3Report[K]{}
+	merge := func(k K) {
+		b, in_base := base[k]
+		o, in_ours := ours[k]
+		t, in_theirs := theirs[k]
+		same := func(x V, x_ok bool, y V, y_ok bool) bool {
+			return x_ok == y_ok && (!x_ok || equal(x, y))
+		}
+		ours_changed := !same(o, in_ours, b, in_base)
+		theirs_changed := !same(t, in_theirs, b, in_base)
+
+		var status Merge3Status
+		switch {
+		case !ours_changed && !theirs_changed:
+			status = Merge3Unchanged
+		case !theirs_changed:
+			status = Merge3OursOnly
+		case !ours_changed:
+			status = Merge3TheirsOnly
+		case same(o, in_ours, t, in_theirs):
+			status = Merge3BothSame
+		case !in_ours || !in_theirs:
+			status = Merge3DeletedOneSide
+		default:
+			status = Merge3Conflict
+		}
+		report[k] = status
+
+		if status == Merge3TheirsOnly {
+			if in_theirs {
+				merged[k] = t
+			}
+		} else if in_ours {
+			merged[k] = o
+		}
+	}
+
+	for k := range base {
+		merge(k)
+	}
+	for k := range ours {
+		if _, in_base := base[k]; !in_base {
+			merge(k)
+		}
+	}
+	for k := range theirs {
+		_, in_base := base[k]
+		_, in_ours := ours[k]
+		if !in_base && !in_ours {
+			merge(k)
+		}
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package maps
+
+import (
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestMerge3(t *testing.T) {
+	base := map[string]int{
+		"unchanged":      1,
+		"ours-modified":  1,
+		"ours-deleted":   1,
+		"theirs-mod":     1,
+		"theirs-deleted": 1,
+		"both-modified":  1,
+		"both-deleted":   1,
+		"conflict":       1,
+		"del-vs-mod":     1,
+		"mod-vs-del":     1,
+	}
+	ours := map[string]int{
+		"unchanged":      1,
+		"ours-modified":  2,
+		"theirs-mod":     1,
+		"theirs-deleted": 1,
+		"both-modified":  2,
+		"conflict":       2,
+		"mod-vs-del":     2,
+		"ours-added":     1,
+		"both-added":     1,
+		"add-conflict":   1,
+	}
+	theirs := map[string]int{
+		"unchanged":     1,
+		"ours-modified": 1,
+		"ours-deleted":  1,
+		"theirs-mod":    3,
+		"both-modified": 2,
+		"conflict":      3,
+		"del-vs-mod":    3,
+		"theirs-added":  1,
+		"both-added":    1,
+		"add-conflict":  2,
+	}
+
+	merged, report := Merge3(base, ours, theirs)
+
+	wantReport := Merge3Report[string]{
+		"unchanged":      Merge3Unchanged,
+		"ours-modified":  Merge3OursOnly,
+		"ours-deleted":   Merge3OursOnly,
+		"ours-added":     Merge3OursOnly,
+		"theirs-mod":     Merge3TheirsOnly,
+		"theirs-deleted": Merge3TheirsOnly,
+		"theirs-added":   Merge3TheirsOnly,
+		"both-modified":  Merge3BothSame,
+		"both-deleted":   Merge3BothSame,
+		"both-added":     Merge3BothSame,
+		"conflict":       Merge3Conflict,
+		"add-conflict":   Merge3Conflict,
+		"del-vs-mod":     Merge3DeletedOneSide,
+		"mod-vs-del":     Merge3DeletedOneSide,
+	}
+	for k, want := range wantReport {
+		if got, ok := report[k]; !ok || got != want {
+			t.Errorf("report[%q] = %v, want %v", k, got, want)
+		}
+	}
+	if len(report) != len(wantReport) {
+		t.Errorf("len(report) = %d, want %d", len(report), len(wantReport))
+	}
+
+	wantMerged := map[string]int{
+		"unchanged":     1,
+		"ours-modified": 2,
+		"ours-added":    1,
+		"theirs-mod":    3,
+		"theirs-added":  1,
+		"both-modified": 2,
+		"both-added":    1,
+		"conflict":      2,
+		"add-conflict":  1,
+		"mod-vs-del":    2,
+	}
+	if !maps.Equal(merged, wantMerged) {
+		t.Errorf("merged = %v, want %v", merged, wantMerged)
+	}
+
+	wantConflicts := map[string]struct{}{
+		"conflict":     {},
+		"add-conflict": {},
+		"del-vs-mod":   {},
+		"mod-vs-del":   {},
+	}
+	if got := report.Conflicts(); !maps.Equal(got, wantConflicts) {
+		t.Errorf("Conflicts() = %v, want %v", got, wantConflicts)
+	}
+}
@@ -0,0 +1,116 @@
+package maps
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/sets"
+)
+
+// MultiMap associates each key with a set of values. Keys with empty sets of
+// values are never kept in the map, so len(m) is the number of distinct keys.
+//
+// Since MultiMap is defined as map[K]sets.Set[V], it can be used with the
+// functions of this package and indexed directly. Like any map, a MultiMap
+// must be initialized before values are added to it.
+type MultiMap[K comparable, V comparable] map[K]sets.Set[V]
+
+// Add associates the values with the key k.
+func (m MultiMap[K, V]) Add(k K, vals ...V) {
+	if len(vals) == 0 {
+		return
+	}
+	s, exists := m[k]
+	if !exists {
+		s = make(sets.Set[V], len(vals))
+		m[k] = s
+	}
+	s.Add(vals...)
+}
+
+// Remove disassociates the values from the key k. The key is removed from m
+// when no values are left.
+func (m MultiMap[K, V]) Remove(k K, vals ...V) {
+	s, exists := m[k]
+	if !exists {
+		return
+	}
+	s.Remove(vals...)
+	if len(s) == 0 {
+		delete(m, k)
+	}
+}
+
+// RemoveKey removes the key k together with all its values.
+func (m MultiMap[K, V]) RemoveKey(k K) {
+	delete(m, k)
+}
+
+// Get returns a copy of the set of values associated with the key k, so that
+// modifying it does not affect m. The returned set is nil if there is no such
+// key.
+func (m MultiMap[K, V]) Get(k K) sets.Set[V] {
+	s, exists := m[k]
+	if !exists {
+		return nil
+	}
+	return s.Clone()
+}
+
+// Has checks if the value v is associated with the key k.
+func (m MultiMap[K, V]) Has(k K, v V) bool {
+	_, ok := m[k][v]
+	return ok
+}
+
+// HasKey checks if there is a key k in m.
+func (m MultiMap[K, V]) HasKey(k K) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// Keys returns the keys of m as a set.
+func (m MultiMap[K, V]) Keys() sets.Set[K] {
+	return sets.FromKeys(m)
+}
+
+// Values returns the set of all distinct values in m.
+func (m MultiMap[K, V]) Values() sets.Set[V] {
+	r := sets.Set[V]{}
+	for _, s := range m {
+		sets.Merge(r, s)
+	}
+	return r
+}
+
+// Count returns the total number of key-value associations in m.
+func (m MultiMap[K, V]) Count() int {
+	n := 0
+	for _, s := range m {
+		n += len(s)
+	}
+	return n
+}
+
+// All returns an iterator over all key-value associations in m. The pairs
+// will be in an indeterminate order.
+func (m MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, s := range m {
+			for v := range s {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Inverted returns a MultiMap that associates each value in m with the set of
+// its keys.
+func (m MultiMap[K, V]) Inverted() MultiMap[V, K] {
+	r := MultiMap[V, K]{}
+	for k, v := range m.All() {
+		r.Add(v, k)
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/adnsv/go-exp/sets"
+	"golang.org/x/exp/maps"
+)
+
+func TestMultiMap(t *testing.T) {
+	type mm = MultiMap[string, int]
+	tests := []struct {
+		name      string
+		op        func(m mm)
+		want      mm
+		wantCount int
+	}{
+		{"add", func(m mm) { m.Add("c", 1, 2) }, mm{"a": sets.Of(1, 2), "b": sets.Of(3), "c": sets.Of(1, 2)}, 5},
+		{"add existing", func(m mm) { m.Add("a", 2, 3) }, mm{"a": sets.Of(1, 2, 3), "b": sets.Of(3)}, 4},
+		{"add nothing", func(m mm) { m.Add("c") }, mm{"a": sets.Of(1, 2), "b": sets.Of(3)}, 3},
+		{"remove", func(m mm) { m.Remove("a", 1) }, mm{"a": sets.Of(2), "b": sets.Of(3)}, 2},
+		{"remove last", func(m mm) { m.Remove("b", 3) }, mm{"a": sets.Of(1, 2)}, 2},
+		{"remove missing", func(m mm) { m.Remove("c", 1); m.Remove("a", 5) }, mm{"a": sets.Of(1, 2), "b": sets.Of(3)}, 3},
+		{"remove key", func(m mm) { m.RemoveKey("a") }, mm{"b": sets.Of(3)}, 1},
+		{"remove all", func(m mm) { m.Remove("a", 1, 2); m.RemoveKey("b") }, mm{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mm{}
+			m.Add("a", 1, 2)
+			m.Add("b", 3)
+			tt.op(m)
+			if !maps.EqualFunc(m, tt.want, sets.Set[int].Equal) {
+				t.Errorf("got %v, want %v", m, tt.want)
+			}
+			if m.Count() != tt.wantCount || len(m) != len(tt.want) {
+				t.Errorf("Count() = %d, len = %d, want %d, %d", m.Count(), len(m), tt.wantCount, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestMultiMapGetReturnsCopy(t *testing.T) {
+	m := MultiMap[string, int]{}
+	m.Add("a", 1)
+	m.Get("a").Remove(1)
+	if !m.Has("a", 1) || len(m) != 1 || m.Count() != 1 {
+		t.Errorf("modifying the result of Get() changed the map: %v", m)
+	}
+	if m.Get("b") != nil {
+		t.Errorf("Get() of a missing key is not nil")
+	}
+}
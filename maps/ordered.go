@@ -0,0 +1,238 @@
+package maps
+
+import "iter"
+
+// OrderedMap is a map that remembers the order in which the keys were
+// inserted. It combines a hash index for O(1) lookups with a doubly linked
+// list that defines the iteration order.
+//
+// The zero value is an empty map ready to use. An OrderedMap must not be
+// copied after first use and is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	index map[K]*orderedEntry[K, V]
+	root  orderedEntry[K, V] // sentinel, root.next is the front, root.prev is the back
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *orderedEntry[K, V]
+}
+
+// NewOrderedMap returns an ordered map populated with the pairs in the order
+// given. Pairs with duplicate keys are handled as in InsertOrOverwrite.
+func NewOrderedMap[K comparable, V any](pairs ...*Pair[K, V]) *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{}
+	m.InsertOrOverwrite(pairs...)
+	return m
+}
+
+func (m *OrderedMap[K, V]) lazyInit() {
+	if m.index == nil {
+		m.index = map[K]*orderedEntry[K, V]{}
+		m.root.next = &m.root
+		m.root.prev = &m.root
+	}
+}
+
+// Len returns the number of elements in m.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.index)
+}
+
+// Has checks if there is a key in m.
+func (m *OrderedMap[K, V]) Has(k K) bool {
+	_, ok := m.index[k]
+	return ok
+}
+
+// Get returns the value associated with the key k.
+func (m *OrderedMap[K, V]) Get(k K) (v V, ok bool) {
+	e, ok := m.index[k]
+	if ok {
+		v = e.val
+	}
+	return
+}
+
+// Set associates the value v with the key k. New keys are appended to the
+// back of m, existing keys keep their position.
+func (m *OrderedMap[K, V]) Set(k K, v V) {
+	if e, ok := m.index[k]; ok {
+		e.val = v
+		return
+	}
+	m.lazyInit()
+	e := &orderedEntry[K, V]{key: k, val: v}
+	m.index[k] = e
+	m.link(e, m.root.prev)
+}
+
+// Delete removes the key k from m. Returns false if there was no such key.
+func (m *OrderedMap[K, V]) Delete(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	delete(m.index, k)
+	m.unlink(e)
+	return true
+}
+
+// Clear removes all elements from m.
+func (m *OrderedMap[K, V]) Clear() {
+	m.index = nil
+	m.root.next = nil
+	m.root.prev = nil
+}
+
+// MoveToFront moves the key k to the front of m. Returns false if there was no
+// such key.
+func (m *OrderedMap[K, V]) MoveToFront(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	if m.root.next != e {
+		m.unlink(e)
+		m.link(e, &m.root)
+	}
+	return true
+}
+
+// MoveToBack moves the key k to the back of m. Returns false if there was no
+// such key.
+func (m *OrderedMap[K, V]) MoveToBack(k K) bool {
+	e, ok := m.index[k]
+	if !ok {
+		return false
+	}
+	if m.root.prev != e {
+		m.unlink(e)
+		m.link(e, m.root.prev)
+	}
+	return true
+}
+
+// Front returns the key-value pair at the front of m (the oldest, unless
+// reordered with MoveToFront or MoveToBack).
+func (m *OrderedMap[K, V]) Front() (k K, v V, ok bool) {
+	if len(m.index) == 0 {
+		return
+	}
+	e := m.root.next
+	return e.key, e.val, true
+}
+
+// Back returns the key-value pair at the back of m (the newest, unless
+// reordered with MoveToFront or MoveToBack).
+func (m *OrderedMap[K, V]) Back() (k K, v V, ok bool) {
+	if len(m.index) == 0 {
+		return
+	}
+	e := m.root.prev
+	return e.key, e.val, true
+}
+
+// Insert copies key-value pairs into m, if m doesn't already contain elements
+// with equivalent keys. This is the OrderedMap counterpart of the Insert
+// function.
+func (m *OrderedMap[K, V]) Insert(pairs ...*Pair[K, V]) {
+	for _, p := range pairs {
+		if !m.Has(p.Key) {
+			m.Set(p.Key, p.Val)
+		}
+	}
+}
+
+// InsertOrOverwrite copies key-value pairs into m, overwriting existing
+// elements with equivalent keys. This is the OrderedMap counterpart of the
+// InsertOrOverwrite function.
+func (m *OrderedMap[K, V]) InsertOrOverwrite(pairs ...*Pair[K, V]) {
+	for _, p := range pairs {
+		m.Set(p.Key, p.Val)
+	}
+}
+
+// Keys returns the keys of m in order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	r := make([]K, 0, len(m.index))
+	for k := range m.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Pairs returns a slice of key-value pairs constructed from m in order. The
+// result can be passed to Insert and InsertOrOverwrite.
+func (m *OrderedMap[K, V]) Pairs() []*Pair[K, V] {
+	r := make([]*Pair[K, V], 0, len(m.index))
+	for k, v := range m.All() {
+		r = append(r, &Pair[K, V]{k, v})
+	}
+	return r
+}
+
+// Map returns a plain map constructed from m.
+func (m *OrderedMap[K, V]) Map() map[K]V {
+	r := make(map[K]V, len(m.index))
+	for k, e := range m.index {
+		r[k] = e.val
+	}
+	return r
+}
+
+// All returns an iterator over key-value pairs in m, from front to back. It is
+// safe to delete any keys during the iteration, deleted keys that have not been
+// reached yet are skipped.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if len(m.index) == 0 {
+			return
+		}
+		for e := m.root.next; e != &m.root; e = e.next {
+			if m.live(e) && !yield(e.key, e.val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over key-value pairs in m, from back to front.
+// It is safe to delete any keys during the iteration, deleted keys that have
+// not been reached yet are skipped.
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if len(m.index) == 0 {
+			return
+		}
+		for e := m.root.prev; e != &m.root; e = e.prev {
+			if m.live(e) && !yield(e.key, e.val) {
+				return
+			}
+		}
+	}
+}
+
+// live checks if e is still in m. Iterators use it to skip the entries that
+// were deleted while they were suspended.
+func (m *OrderedMap[K, V]) live(e *orderedEntry[K, V]) bool {
+	return m.index[e.key] == e
+}
+
+// link inserts e after at.
+func (m *OrderedMap[K, V]) link(e, at *orderedEntry[K, V]) {
+	e.prev = at
+	e.next = at.next
+	at.next.prev = e
+	at.next = e
+}
+
+// unlink removes e from the list. The links of e are kept, so that an
+// iterator suspended at e can still advance after e is deleted: the entries
+// they lead to were in the list when e was removed, and following them
+// eventually reaches an entry that is still in the list, or the sentinel.
+func (m *OrderedMap[K, V]) unlink(e *orderedEntry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
@@ -0,0 +1,128 @@
+package maps
+
+import (
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func TestOrderedMap(t *testing.T) {
+	type pair = Pair[int, string]
+	m := NewOrderedMap(&pair{3, "three"}, &pair{1, "one"}, &pair{2, "two"})
+
+	check := func(name string, want ...int) {
+		t.Helper()
+		if got := m.Keys(); !slices.Equal(got, want) {
+			t.Errorf("%s: Keys() = %v, want %v", name, got, want)
+		}
+		if m.Len() != len(want) {
+			t.Errorf("%s: Len() = %d, want %d", name, m.Len(), len(want))
+		}
+	}
+
+	check("new", 3, 1, 2)
+
+	m.Set(1, "ONE")
+	check("overwrite keeps position", 3, 1, 2)
+	if v, ok := m.Get(1); !ok || v != "ONE" {
+		t.Errorf("Get(1) = %q, %v, want %q, true", v, ok, "ONE")
+	}
+
+	m.MoveToBack(3)
+	check("move to back", 1, 2, 3)
+	m.MoveToFront(2)
+	check("move to front", 2, 1, 3)
+	if m.MoveToFront(42) || m.MoveToBack(42) {
+		t.Errorf("MoveTo*() = true for a missing key")
+	}
+
+	m.Insert(&pair{1, "uno"}, &pair{4, "four"})
+	check("insert", 2, 1, 3, 4)
+	if v, _ := m.Get(1); v != "ONE" {
+		t.Errorf("Insert() overwrote an existing key: %q", v)
+	}
+
+	m.Delete(1)
+	check("delete", 2, 3, 4)
+	if m.Delete(1) {
+		t.Errorf("Delete() = true for a missing key")
+	}
+
+	var backward []int
+	for k := range m.Backward() {
+		backward = append(backward, k)
+	}
+	if want := []int{4, 3, 2}; !slices.Equal(backward, want) {
+		t.Errorf("Backward() = %v, want %v", backward, want)
+	}
+
+	for k := range m.All() {
+		if k != 3 {
+			m.Delete(k)
+		}
+	}
+	check("delete while iterating", 3)
+
+	if k, _, ok := m.Front(); !ok || k != 3 {
+		t.Errorf("Front() = %v, %v, want 3, true", k, ok)
+	}
+
+	m.Clear()
+	check("clear")
+	if _, _, ok := m.Back(); ok {
+		t.Errorf("Back() = _, _, true for an empty map")
+	}
+	m.Set(5, "five")
+	check("set after clear", 5)
+}
+
+func TestOrderedMapDeleteWhileIterating(t *testing.T) {
+	tests := []struct {
+		name    string
+		forward bool
+		del     func(k int) []int
+		want    []int
+	}{
+		{"next", true, func(k int) []int { return []int{k + 1} }, []int{0, 2, 4, 6, 8}},
+		{"current and next", true, func(k int) []int { return []int{k, k + 1, k + 2} }, []int{0, 3, 6, 9}},
+		{"visited", true, func(k int) []int { return []int{k - 1} }, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"all", true, func(k int) []int { return []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9} }, []int{0}},
+		{"previous", false, func(k int) []int { return []int{k - 1} }, []int{9, 7, 5, 3, 1}},
+		{"current and previous", false, func(k int) []int { return []int{k, k - 1, k - 2} }, []int{9, 6, 3, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &OrderedMap[int, int]{}
+			for i := 0; i < 10; i++ {
+				m.Set(i, i)
+			}
+			it := m.All()
+			if !tt.forward {
+				it = m.Backward()
+			}
+			var got []int
+			for k := range it {
+				got = append(got, k)
+				for _, d := range tt.del(k) {
+					m.Delete(d)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("visited %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderedMapInterop(t *testing.T) {
+	m := OrderedMap[int, string]{}
+	m.Set(2, "two")
+	m.Set(1, "one")
+
+	plain := map[int]string{1: "uno", 3: "tres"}
+	Insert(plain, m.Pairs()...)
+	if want := map[int]string{1: "uno", 2: "two", 3: "tres"}; !maps.Equal(plain, want) {
+		t.Errorf("Insert(m.Pairs()) = %v, want %v", plain, want)
+	}
+}
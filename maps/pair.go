@@ -104,6 +104,10 @@ func InsertOrOverwrite[M ~map[K]V, K comparable, V any](m M, pairs ...*Pair[K, V
 
 // sorting callbacks, used internally
 
+func lessOrdered[K constraints.Ordered](a, b K) bool {
+	return a < b
+}
+
 func lessByKey[K constraints.Ordered, V any](a, b *Pair[K, V]) bool {
 	return a.Key < b.Key
 }
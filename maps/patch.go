@@ -0,0 +1,268 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PatchAction is the kind of a PatchOp.
+type PatchAction int
+
+const (
+	// PatchSet adds a key that is expected to be absent.
+	PatchSet PatchAction = iota
+	// PatchDelete removes a key that is expected to hold the Old value.
+	PatchDelete
+	// PatchReplace overwrites a key that is expected to hold the Old value.
+	PatchReplace
+)
+
+func (a PatchAction) String() string {
+	switch a {
+	case PatchSet:
+		return "set"
+	case PatchDelete:
+		return "delete"
+	case PatchReplace:
+		return "replace"
+	default:
+		return fmt.Sprintf("PatchAction(%d)", int(a))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a PatchAction) MarshalText() ([]byte, error) {
+	switch a {
+	case PatchSet, PatchDelete, PatchReplace:
+		return []byte(a.String()), nil
+	default:
+		return nil, fmt.Errorf("maps: invalid patch action %d", int(a))
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *PatchAction) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "set":
+		*a = PatchSet
+	case "delete":
+		*a = PatchDelete
+	case "replace":
+		*a = PatchReplace
+	default:
+		return fmt.Errorf("maps: invalid patch action %q", text)
+	}
+	return nil
+}
+
+// ErrPatchFailed is returned (wrapped) when a patch can not be applied because
+// the map does not hold the expected values.
+var ErrPatchFailed = errors.New("maps: patch precondition failed")
+
+// PatchOp is a single change of a Patch. Old is only meaningful for
+// PatchDelete and PatchReplace, Val is only meaningful for PatchSet and
+// PatchReplace.
+type PatchOp[K comparable, V comparable] struct {
+	Action PatchAction
+	Key    K
+	Old    V
+	Val    V
+}
+
+type patchOpJSON[K comparable, V comparable] struct {
+	Action PatchAction `json:"op"`
+	Key    K           `json:"key"`
+	Old    *V          `json:"old,omitempty"`
+	Val    *V          `json:"value,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Only the fields that are meaningful
+// for the action are emitted.
+func (op PatchOp[K, V]) MarshalJSON() ([]byte, error) {
+	j := patchOpJSON[K, V]{Action: op.Action, Key: op.Key}
+	if op.Action != PatchSet {
+		j.Old = &op.Old
+	}
+	if op.Action != PatchDelete {
+		j.Val = &op.Val
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (op *PatchOp[K, V]) UnmarshalJSON(data []byte) error {
+	var j patchOpJSON[K, V]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Action != PatchSet && j.Old == nil {
+		return fmt.Errorf("maps: %s of %v is missing the old value", j.Action, j.Key)
+	}
+	if j.Action != PatchDelete && j.Val == nil {
+		return fmt.Errorf("maps: %s of %v is missing the value", j.Action, j.Key)
+	}
+	*op = PatchOp[K, V]{Action: j.Action, Key: j.Key}
+	if j.Old != nil {
+		op.Old = *j.Old
+	}
+	if j.Val != nil {
+		op.Val = *j.Val
+	}
+	return nil
+}
+
+// invert returns the operation that undoes op.
+func (op PatchOp[K, V]) invert() PatchOp[K, V] {
+	switch op.Action {
+	case PatchSet:
+		return PatchOp[K, V]{Action: PatchDelete, Key: op.Key, Old: op.Val}
+	case PatchDelete:
+		return PatchOp[K, V]{Action: PatchSet, Key: op.Key, Val: op.Old}
+	default:
+		return PatchOp[K, V]{Action: PatchReplace, Key: op.Key, Old: op.Val, Val: op.Old}
+	}
+}
+
+// Patch is a reusable change-set that can be applied to maps, inverted,
+// composed and serialized to JSON. Every operation carries its expectation of
+// the current state, which makes applying a patch an optimistic, all-or-nothing
+// update.
+type Patch[K comparable, V comparable] []PatchOp[K, V]
+
+// CalcPatch calculates a patch that turns the map from into the map to.
+//
+//   - Data in both from and to remains unchanged
+//   - The operations will be in an indeterminate order
+func CalcPatch[M1 ~map[K]V, M2 ~map[K]V, K comparable, V comparable](from M1, to M2) Patch[K, V] {
+	return PatchFromDiff(Diff(from, to))
+}
+
+// PatchFromDiff converts the differences calculated by Diff into a patch.
+func PatchFromDiff[K comparable, V comparable](d *DiffResult[K, V]) Patch[K, V] {
+	p := make(Patch[K, V], 0, len(d.Added)+len(d.Removed)+len(d.Changed))
+	for k, v := range d.Added {
+		p = append(p, PatchOp[K, V]{Action: PatchSet, Key: k, Val: v})
+	}
+	for k, v := range d.Removed {
+		p = append(p, PatchOp[K, V]{Action: PatchDelete, Key: k, Old: v})
+	}
+	for k, c := range d.Changed {
+		p = append(p, PatchOp[K, V]{Action: PatchReplace, Key: k, Old: c.Old, Val: c.New})
+	}
+	return p
+}
+
+// patchState is the presence and the value of a key, as seen while a patch is
+// being evaluated.
+type patchState[V comparable] struct {
+	exists bool
+	val    V
+}
+
+// step checks the precondition of op against the state s and returns the
+// state after op.
+func (op PatchOp[K, V]) step(s patchState[V]) (patchState[V], error) {
+	switch op.Action {
+	case PatchSet:
+		if s.exists {
+			return s, fmt.Errorf("%w: set %v: key exists", ErrPatchFailed, op.Key)
+		}
+		return patchState[V]{true, op.Val}, nil
+	case PatchDelete, PatchReplace:
+		if !s.exists {
+			return s, fmt.Errorf("%w: %s %v: key does not exist", ErrPatchFailed, op.Action, op.Key)
+		}
+		if s.val != op.Old {
+			return s, fmt.Errorf("%w: %s %v: have %v, want %v", ErrPatchFailed, op.Action, op.Key, s.val, op.Old)
+		}
+		if op.Action == PatchDelete {
+			return patchState[V]{}, nil
+		}
+		return patchState[V]{true, op.Val}, nil
+	default:
+		return s, fmt.Errorf("maps: invalid patch action %d", int(op.Action))
+	}
+}
+
+// Apply applies the patch to m. All the preconditions are checked before m is
+// modified: if any of them fails, m is left unchanged and the returned error
+// wraps ErrPatchFailed.
+func (p Patch[K, V]) Apply(m map[K]V) error {
+	pending := map[K]patchState[V]{}
+	for _, op := range p {
+		s, seen := pending[op.Key]
+		if !seen {
+			s.val, s.exists = m[op.Key]
+		}
+		s, err := op.step(s)
+		if err != nil {
+			return err
+		}
+		pending[op.Key] = s
+	}
+	for k, s := range pending {
+		if s.exists {
+			m[k] = s.val
+		} else {
+			delete(m, k)
+		}
+	}
+	return nil
+}
+
+// Invert returns a patch that undoes p. Applying p and then its inverse
+// leaves a map unchanged.
+func (p Patch[K, V]) Invert() Patch[K, V] {
+	r := make(Patch[K, V], len(p))
+	for i, op := range p {
+		r[len(p)-1-i] = op.invert()
+	}
+	return r
+}
+
+// Compose returns a single patch that has the same effect as applying p and
+// then q. Operations on the same key are folded together, keys that end up
+// unchanged are dropped. An error wrapping ErrPatchFailed is returned if q
+// expects a state that p does not produce.
+func (p Patch[K, V]) Compose(q Patch[K, V]) (Patch[K, V], error) {
+	type fold struct {
+		before, after patchState[V]
+	}
+	var keys []K
+	folds := map[K]*fold{}
+	for _, ops := range []Patch[K, V]{p, q} {
+		for _, op := range ops {
+			f, seen := folds[op.Key]
+			if !seen {
+				// the first operation on a key defines what is expected
+				// before the composed patch is applied
+				f = &fold{}
+				if op.Action != PatchSet {
+					f.before = patchState[V]{true, op.Old}
+				}
+				f.after = f.before
+				folds[op.Key] = f
+				keys = append(keys, op.Key)
+			}
+			s, err := op.step(f.after)
+			if err != nil {
+				return nil, err
+			}
+			f.after = s
+		}
+	}
+	r := make(Patch[K, V], 0, len(keys))
+	for _, k := range keys {
+		f := folds[k]
+		switch {
+		case !f.before.exists && f.after.exists:
+			r = append(r, PatchOp[K, V]{Action: PatchSet, Key: k, Val: f.after.val})
+		case f.before.exists && !f.after.exists:
+			r = append(r, PatchOp[K, V]{Action: PatchDelete, Key: k, Old: f.before.val})
+		case f.before.exists && f.before.val != f.after.val:
+			r = append(r, PatchOp[K, V]{Action: PatchReplace, Key: k, Old: f.before.val, Val: f.after.val})
+		}
+	}
+	return r, nil
+}
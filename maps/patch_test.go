@@ -0,0 +1,130 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestPatchApplyInvert(t *testing.T) {
+	from := map[string]int{"a": 1, "b": 2, "c": 3}
+	to := map[string]int{"a": 1, "b": 20, "d": 4}
+
+	p := CalcPatch(from, to)
+	if len(p) != 3 {
+		t.Fatalf("CalcPatch() produced %d ops, want 3", len(p))
+	}
+
+	m := maps.Clone(from)
+	if err := p.Apply(m); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if !maps.Equal(m, to) {
+		t.Fatalf("Apply() = %v, want %v", m, to)
+	}
+
+	if err := p.Invert().Apply(m); err != nil {
+		t.Fatalf("Invert().Apply() = %v", err)
+	}
+	if !maps.Equal(m, from) {
+		t.Fatalf("Invert().Apply() = %v, want %v", m, from)
+	}
+}
+
+func TestPatchApplyConflict(t *testing.T) {
+	type op = PatchOp[string, int]
+	tests := []struct {
+		name string
+		p    Patch[string, int]
+	}{
+		{"set existing", Patch[string, int]{{Action: PatchSet, Key: "a", Val: 1}}},
+		{"delete missing", Patch[string, int]{{Action: PatchDelete, Key: "x", Old: 1}}},
+		{"delete stale", Patch[string, int]{{Action: PatchDelete, Key: "a", Old: 2}}},
+		{"replace stale", Patch[string, int]{{Action: PatchReplace, Key: "a", Old: 2, Val: 3}}},
+		{"second op fails", Patch[string, int]{
+			op{Action: PatchReplace, Key: "b", Old: 2, Val: 3},
+			op{Action: PatchReplace, Key: "b", Old: 2, Val: 4},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string]int{"a": 1, "b": 2}
+			err := tt.p.Apply(m)
+			if !errors.Is(err, ErrPatchFailed) {
+				t.Errorf("Apply() = %v, want ErrPatchFailed", err)
+			}
+			if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(m, want) {
+				t.Errorf("failed Apply() modified the map: %v", m)
+			}
+		})
+	}
+}
+
+func TestPatchCompose(t *testing.T) {
+	v1 := map[string]int{"a": 1, "b": 2, "c": 3}
+	v2 := map[string]int{"a": 10, "c": 3, "d": 4}
+	v3 := map[string]int{"a": 1, "c": 30, "e": 5}
+
+	p, err := CalcPatch(v1, v2).Compose(CalcPatch(v2, v3))
+	if err != nil {
+		t.Fatalf("Compose() = %v", err)
+	}
+	m := maps.Clone(v1)
+	if err := p.Apply(m); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if !maps.Equal(m, v3) {
+		t.Errorf("Apply() = %v, want %v", m, v3)
+	}
+	// "a" goes 1 -> 10 -> 1 and "d" is added and then removed
+	for _, op := range p {
+		if op.Key == "a" || op.Key == "d" {
+			t.Errorf("Compose() kept a no-op for %q", op.Key)
+		}
+	}
+
+	if _, err := CalcPatch(v1, v2).Compose(CalcPatch(v1, v3)); !errors.Is(err, ErrPatchFailed) {
+		t.Errorf("Compose() of unrelated patches = %v, want ErrPatchFailed", err)
+	}
+}
+
+func TestPatchJSON(t *testing.T) {
+	p := Patch[string, int]{
+		{Action: PatchSet, Key: "a", Val: 1},
+		{Action: PatchDelete, Key: "b", Old: 2},
+		{Action: PatchReplace, Key: "c", Old: 3, Val: 0},
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	want := `[{"op":"set","key":"a","value":1},{"op":"delete","key":"b","old":2},{"op":"replace","key":"c","old":3,"value":0}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got Patch[string, int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	if len(got) != len(p) {
+		t.Fatalf("Unmarshal() = %v, want %v", got, p)
+	}
+	for i := range p {
+		if got[i] != p[i] {
+			t.Errorf("Unmarshal()[%d] = %v, want %v", i, got[i], p[i])
+		}
+	}
+
+	for _, bad := range []string{
+		`[{"op":"merge","key":"a","value":1}]`,
+		`[{"op":"replace","key":"a","value":1}]`,
+		`[{"op":"set","key":"a"}]`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", bad)
+		}
+	}
+}
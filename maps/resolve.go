@@ -0,0 +1,127 @@
+package maps
+
+import (
+	"errors"
+
+	"github.com/adnsv/go-exp/sets"
+	"golang.org/x/exp/constraints"
+)
+
+// Resolution is the outcome of a conflict resolver used with MergeWith.
+type Resolution int
+
+const (
+	// Resolved stores the value returned by the resolver in dst.
+	Resolved Resolution = iota
+	// Unresolved leaves dst unchanged and reports the src value in conflicts.
+	Unresolved
+	// Failed stops the merge, MergeWith returns a *ConflictError that wraps
+	// ErrMergeFailed.
+	Failed
+)
+
+// ErrMergeFailed is wrapped by the *ConflictError that MergeWith returns when
+// the resolver fails a conflict.
+var ErrMergeFailed = errors.New("maps: merge failed")
+
+// MergeWith copies key/value pairs in src adding them to dst. When a key from
+// src is already in dst, the resolve function is called to combine both
+// values, even if they are equal. If the conflict is resolved, the returned
+// value is stored in dst, if it is unresolved, the whole key/value pair from
+// src is copied to conflicts, the same way Merge reports them.
+//
+// If the resolver fails a conflict, MergeWith stops and returns a
+// *ConflictError with the key and both values, which wraps ErrMergeFailed. In
+// this case, dst is left unchanged.
+//
+// This package provides ready-made resolvers: KeepDst, TakeSrc, Sum, Max, Min,
+// Concat, Union, Reject and Error. For example:
+//
+//	conflicts, err := MergeWith(totals, counts, Sum)
+func MergeWith[M1 ~map[K]V, M2 ~map[K]V, K comparable, V any](dst M1, src M2, resolve func(k K, dst, src V) (V, Resolution)) (conflicts M2, err error) {
+	// resolve all conflicts before touching dst
+	resolved := map[K]V{}
+	conflicts = M2{}
+	for k, v := range src {
+		prev_v, exists := dst[k]
+		if !exists {
+			continue
+		}
+		switch r, res := resolve(k, prev_v, v); res {
+		case Resolved:
+			resolved[k] = r
+		case Unresolved:
+			conflicts[k] = v
+		default:
+			return nil, &ConflictError[K, V]{
+				Conflicts: []Conflict[K, V]{{k, prev_v, v}},
+				cause:     ErrMergeFailed,
+			}
+		}
+	}
+	for k, v := range src {
+		if r, ok := resolved[k]; ok {
+			dst[k] = r
+		} else if _, ok := conflicts[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return conflicts, nil
+}
+
+type number interface {
+	constraints.Integer | constraints.Float | constraints.Complex
+}
+
+// KeepDst is a MergeWith resolver that keeps the dst value.
+func KeepDst[K comparable, V any](k K, dst, src V) (V, Resolution) {
+	return dst, Resolved
+}
+
+// TakeSrc is a MergeWith resolver that overwrites dst with the src value.
+func TakeSrc[K comparable, V any](k K, dst, src V) (V, Resolution) {
+	return src, Resolved
+}
+
+// Sum is a MergeWith resolver that stores the sum of both values.
+func Sum[K comparable, V number](k K, dst, src V) (V, Resolution) {
+	return dst + src, Resolved
+}
+
+// Max is a MergeWith resolver that stores the larger of both values.
+func Max[K comparable, V constraints.Ordered](k K, dst, src V) (V, Resolution) {
+	return max(dst, src), Resolved
+}
+
+// Min is a MergeWith resolver that stores the smaller of both values.
+func Min[K comparable, V constraints.Ordered](k K, dst, src V) (V, Resolution) {
+	return min(dst, src), Resolved
+}
+
+// Concat is a MergeWith resolver for slice values that stores a new slice with
+// the dst elements followed by the src elements.
+func Concat[K comparable, S ~[]E, E any](k K, dst, src S) (S, Resolution) {
+	r := make(S, 0, len(dst)+len(src))
+	r = append(r, dst...)
+	return append(r, src...), Resolved
+}
+
+// Union is a MergeWith resolver for set values that stores a new set with the
+// keys from both sets.
+func Union[K comparable, S ~map[E]struct{}, E comparable](k K, dst, src S) (S, Resolution) {
+	return sets.Union(dst, src), Resolved
+}
+
+// Reject is a MergeWith resolver that leaves every conflict unresolved. Unlike
+// Merge, which skips keys with equal values, all keys that are already in dst
+// are reported in conflicts, since the values are not compared.
+func Reject[K comparable, V any](k K, dst, src V) (V, Resolution) {
+	return dst, Unresolved
+}
+
+// Error is a MergeWith resolver that fails on the first key that is already in
+// dst, regardless of the values. MergeWith then returns an error and leaves dst
+// unchanged.
+func Error[K comparable, V any](k K, dst, src V) (V, Resolution) {
+	return dst, Failed
+}
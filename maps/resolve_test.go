@@ -0,0 +1,79 @@
+package maps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adnsv/go-exp/sets"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func TestMergeWithResolvers(t *testing.T) {
+	tests := []struct {
+		name          string
+		resolve       func(k string, dst, src int) (int, Resolution)
+		want          map[string]int
+		wantConflicts map[string]int
+	}{
+		{"KeepDst", KeepDst[string, int], map[string]int{"a": 1, "b": 5, "c": 3}, map[string]int{}},
+		{"TakeSrc", TakeSrc[string, int], map[string]int{"a": 1, "b": 2, "c": 3}, map[string]int{}},
+		{"Sum", Sum[string, int], map[string]int{"a": 1, "b": 7, "c": 3}, map[string]int{}},
+		{"Max", Max[string, int], map[string]int{"a": 1, "b": 5, "c": 3}, map[string]int{}},
+		{"Min", Min[string, int], map[string]int{"a": 1, "b": 2, "c": 3}, map[string]int{}},
+		{"Reject", Reject[string, int], map[string]int{"a": 1, "b": 5, "c": 3}, map[string]int{"b": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := map[string]int{"a": 1, "b": 5}
+			conflicts, err := MergeWith(dst, map[string]int{"b": 2, "c": 3}, tt.resolve)
+			if err != nil {
+				t.Fatalf("MergeWith() error = %v", err)
+			}
+			if !maps.Equal(dst, tt.want) {
+				t.Errorf("MergeWith() = %v, want %v", dst, tt.want)
+			}
+			if !maps.Equal(conflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestMergeWithError(t *testing.T) {
+	dst := map[string]int{"a": 1, "b": 5}
+	conflicts, err := MergeWith(dst, map[string]int{"b": 5, "c": 3}, Error[string, int])
+	if !errors.Is(err, ErrMergeFailed) || !errors.Is(err, ErrConflict) || conflicts != nil {
+		t.Errorf("MergeWith() = %v, %v", conflicts, err)
+	}
+	var ce *ConflictError[string, int]
+	if !errors.As(err, &ce) || len(ce.Conflicts) != 1 || ce.Conflicts[0] != (Conflict[string, int]{"b", 5, 5}) {
+		t.Errorf("MergeWith() error = %#v", err)
+	}
+	if want := "maps: merge failed: conflicting key b (5 vs 5)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if want := map[string]int{"a": 1, "b": 5}; !maps.Equal(dst, want) {
+		t.Errorf("dst = %v, want %v", dst, want)
+	}
+
+	// keys that are not in dst do not fail
+	conflicts, err = MergeWith(dst, map[string]int{"c": 3}, Error[string, int])
+	if err != nil || len(conflicts) != 0 || dst["c"] != 3 {
+		t.Errorf("MergeWith() = %v, %v", conflicts, err)
+	}
+}
+
+func TestMergeWithCollections(t *testing.T) {
+	lists := map[string][]int{"a": {1, 2}}
+	MergeWith(lists, map[string][]int{"a": {2, 3}}, Concat)
+	if want := []int{1, 2, 2, 3}; !slices.Equal(lists["a"], want) {
+		t.Errorf("Concat = %v, want %v", lists["a"], want)
+	}
+
+	tags := map[string]sets.Set[string]{"a": sets.Of("x", "y")}
+	MergeWith(tags, map[string]sets.Set[string]{"a": sets.Of("y", "z")}, Union)
+	if want := sets.Of("x", "y", "z"); !tags["a"].Equal(want) {
+		t.Errorf("Union = %v, want %v", tags["a"].Keys(), want.Keys())
+	}
+}
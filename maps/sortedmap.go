@@ -0,0 +1,177 @@
+package maps
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/tree"
+	"golang.org/x/exp/constraints"
+)
+
+// SortedMapFunc is a map that keeps its keys sorted as determined by a less
+// function. It is backed by a balanced binary search tree, so that lookups,
+// updates and order-statistic queries take O(log n), and iterating in key
+// order does not require sorting.
+//
+// Use NewSortedMapFunc to create a SortedMapFunc. The zero value has no less
+// function: it reads as an empty map, but Set panics. A SortedMapFunc must not
+// be copied after first use and is not safe for concurrent use.
+type SortedMapFunc[K any, V any] struct {
+	t tree.Tree[K, V]
+}
+
+// SortedMap is a SortedMapFunc for ordered keys.
+//
+// The zero value is an empty map ready to use.
+type SortedMap[K constraints.Ordered, V any] struct {
+	SortedMapFunc[K, V]
+}
+
+// NewSortedMapFunc returns an empty map with keys sorted as determined by the
+// less function.
+func NewSortedMapFunc[K any, V any](less func(a, b K) bool) *SortedMapFunc[K, V] {
+	return &SortedMapFunc[K, V]{*tree.New[K, V](less)}
+}
+
+// NewSortedMap returns an empty map with sorted keys.
+func NewSortedMap[K constraints.Ordered, V any]() *SortedMap[K, V] {
+	m := &SortedMap[K, V]{}
+	m.lazyInit()
+	return m
+}
+
+// SortedMapFrom returns a SortedMap constructed from the key-value pairs in m.
+func SortedMapFrom[M ~map[K]V, K constraints.Ordered, V any](m M) *SortedMap[K, V] {
+	keys := SortedKeys(m)
+	vals := make([]V, len(keys))
+	for i, k := range keys {
+		vals[i] = m[k]
+	}
+	return &SortedMap[K, V]{SortedMapFunc[K, V]{*tree.Build(lessOrdered[K], keys, vals)}}
+}
+
+func (m *SortedMap[K, V]) lazyInit() {
+	if m.t.Less() == nil {
+		m.t = *tree.New[K, V](lessOrdered[K])
+	}
+}
+
+// Len returns the number of elements in m.
+func (m *SortedMapFunc[K, V]) Len() int {
+	return m.t.Len()
+}
+
+// Has checks if there is a key in m.
+func (m *SortedMapFunc[K, V]) Has(k K) bool {
+	_, ok := m.t.Get(k)
+	return ok
+}
+
+// Get returns the value associated with the key k.
+func (m *SortedMapFunc[K, V]) Get(k K) (V, bool) {
+	return m.t.Get(k)
+}
+
+// Set associates the value v with the key k. Returns true if k was not in m
+// before.
+func (m *SortedMapFunc[K, V]) Set(k K, v V) bool {
+	if m.t.Less() == nil {
+		panic("maps: SortedMapFunc without a less function, use NewSortedMapFunc")
+	}
+	return m.t.Put(k, v)
+}
+
+// Set associates the value v with the key k. Returns true if k was not in m
+// before.
+func (m *SortedMap[K, V]) Set(k K, v V) bool {
+	m.lazyInit()
+	return m.SortedMapFunc.Set(k, v)
+}
+
+// Delete removes the key k from m. Returns false if there was no such key.
+func (m *SortedMapFunc[K, V]) Delete(k K) bool {
+	_, ok := m.t.Delete(k)
+	return ok
+}
+
+// Clear removes all elements from m.
+func (m *SortedMapFunc[K, V]) Clear() {
+	m.t.Clear()
+}
+
+// Min returns the key-value pair with the smallest key.
+func (m *SortedMapFunc[K, V]) Min() (K, V, bool) {
+	return m.t.Min()
+}
+
+// Max returns the key-value pair with the largest key.
+func (m *SortedMapFunc[K, V]) Max() (K, V, bool) {
+	return m.t.Max()
+}
+
+// Floor returns the key-value pair with the largest key that is less than or
+// equal to k.
+func (m *SortedMapFunc[K, V]) Floor(k K) (K, V, bool) {
+	return m.t.Floor(k)
+}
+
+// Ceiling returns the key-value pair with the smallest key that is greater
+// than or equal to k.
+func (m *SortedMapFunc[K, V]) Ceiling(k K) (K, V, bool) {
+	return m.t.Ceiling(k)
+}
+
+// Rank returns the number of keys in m that are less than k. When k is in m,
+// this is its zero-based position in key order.
+func (m *SortedMapFunc[K, V]) Rank(k K) int {
+	return m.t.Rank(k)
+}
+
+// Select returns the key-value pair at the zero-based position i in key order.
+func (m *SortedMapFunc[K, V]) Select(i int) (K, V, bool) {
+	return m.t.Select(i)
+}
+
+// Range returns an iterator over key-value pairs with keys in the half-open
+// interval [lo, hi), in key order.
+func (m *SortedMapFunc[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.t.Range(lo, hi)
+}
+
+// All returns an iterator over key-value pairs in m, in key order.
+func (m *SortedMapFunc[K, V]) All() iter.Seq2[K, V] {
+	return m.t.All()
+}
+
+// Backward returns an iterator over key-value pairs in m, in reverse key
+// order.
+func (m *SortedMapFunc[K, V]) Backward() iter.Seq2[K, V] {
+	return m.t.Backward()
+}
+
+// Keys returns the keys of m in order.
+func (m *SortedMapFunc[K, V]) Keys() []K {
+	r := make([]K, 0, m.Len())
+	for k := range m.t.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Pairs returns a slice of key-value pairs constructed from m in key order.
+// The result is equivalent to what SortedByKey produces for a plain map.
+func (m *SortedMapFunc[K, V]) Pairs() []*Pair[K, V] {
+	r := make([]*Pair[K, V], 0, m.Len())
+	for k, v := range m.t.All() {
+		r = append(r, &Pair[K, V]{k, v})
+	}
+	return r
+}
+
+// Map returns a plain map constructed from m.
+func (m *SortedMap[K, V]) Map() map[K]V {
+	r := make(map[K]V, m.Len())
+	for k, v := range m.t.All() {
+		r[k] = v
+	}
+	return r
+}
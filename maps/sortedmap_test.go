@@ -0,0 +1,52 @@
+package maps
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSortedMapZeroValue(t *testing.T) {
+	var m SortedMap[int, string]
+	if m.Len() != 0 || m.Has(1) || len(m.Keys()) != 0 {
+		t.Errorf("zero value is not empty")
+	}
+	if _, _, ok := m.Min(); ok {
+		t.Errorf("Min() = _, _, true for an empty map")
+	}
+	if m.Delete(1) {
+		t.Errorf("Delete() = true for an empty map")
+	}
+	for k := range m.Range(0, 10) {
+		t.Errorf("Range() yielded %d for an empty map", k)
+	}
+
+	m.Set(3, "three")
+	m.Set(1, "one")
+	m.Set(2, "two")
+	if got, want := m.Keys(), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if k, _, ok := m.Floor(5); !ok || k != 3 {
+		t.Errorf("Floor(5) = %d, %v, want 3, true", k, ok)
+	}
+
+	m.Clear()
+	m.Set(4, "four")
+	if m.Len() != 1 || m.Rank(4) != 0 {
+		t.Errorf("Set() after Clear() is wrong")
+	}
+}
+
+func TestSortedMapFuncZeroValue(t *testing.T) {
+	var m SortedMapFunc[int, string]
+	if m.Len() != 0 || m.Has(1) {
+		t.Errorf("zero value is not empty")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Set() did not panic without a less function")
+		}
+	}()
+	m.Set(1, "one")
+}
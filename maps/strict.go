@@ -0,0 +1,116 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ErrConflict is matched by errors.Is for every *ConflictError.
+var ErrConflict = errors.New("maps: conflict")
+
+// Conflict describes a key that has different values in dst and src.
+type Conflict[K comparable, V any] struct {
+	Key K
+	Dst V
+	Src V
+}
+
+// ConflictError is returned by MergeStrict and InsertStrict, and by MergeWith
+// when the resolver fails. It lists every conflicting key together with both
+// values. Use errors.As to retrieve it and errors.Is(err, ErrConflict) to detect
+// it regardless of its type arguments. Errors returned by MergeWith also match
+// ErrMergeFailed.
+type ConflictError[K comparable, V any] struct {
+	Conflicts []Conflict[K, V]
+
+	cause error // the sentinel error that is wrapped, if any
+}
+
+// Error returns a message that lists the conflicts sorted by the formatted
+// key.
+func (e *ConflictError[K, V]) Error() string {
+	items := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		items[i] = fmt.Sprintf("%v (%v vs %v)", c.Key, c.Dst, c.Src)
+	}
+	slices.Sort(items)
+	prefix := "maps"
+	if e.cause != nil {
+		prefix = e.cause.Error()
+	}
+	if len(items) == 1 {
+		return prefix + ": conflicting key " + items[0]
+	}
+	return fmt.Sprintf("%s: %d conflicting keys: %s", prefix, len(items), strings.Join(items, ", "))
+}
+
+// Is reports whether target is ErrConflict.
+func (e *ConflictError[K, V]) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Unwrap returns the sentinel error that e wraps, such as ErrMergeFailed for
+// the errors returned by MergeWith, or nil.
+func (e *ConflictError[K, V]) Unwrap() error {
+	return e.cause
+}
+
+// Keys returns the set of conflicting keys.
+func (e *ConflictError[K, V]) Keys() map[K]struct{} {
+	r := make(map[K]struct{}, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		r[c.Key] = struct{}{}
+	}
+	return r
+}
+
+// MergeStrict provides the same functionality as Merge, but reports conflicts
+// as an error. The merge is all-or-nothing: if any key from src is already in
+// dst with a different value, dst is left unchanged and a *ConflictError is
+// returned.
+func MergeStrict[M1 ~map[K]V, M2 ~map[K]V, K comparable, V comparable](dst M1, src M2) error {
+	var conflicts []Conflict[K, V]
+	for k, v := range src {
+		if prev_v, exists := dst[k]; exists && prev_v != v {
+			conflicts = append(conflicts, Conflict[K, V]{k, prev_v, v})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError[K, V]{Conflicts: conflicts}
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return nil
+}
+
+// InsertStrict provides the same functionality as Insert, but reports pairs
+// that would be skipped because of a different existing value as an error.
+// Pairs are checked against m and against the preceding pairs. The insertion
+// is all-or-nothing: on conflicts, m is left unchanged and a *ConflictError is
+// returned.
+func InsertStrict[M ~map[K]V, K comparable, V comparable](m M, pairs ...*Pair[K, V]) error {
+	var conflicts []Conflict[K, V]
+	pending := map[K]V{}
+	for _, p := range pairs {
+		prev_v, exists := pending[p.Key]
+		if !exists {
+			prev_v, exists = m[p.Key]
+		}
+		if !exists {
+			pending[p.Key] = p.Val
+		} else if prev_v != p.Val {
+			conflicts = append(conflicts, Conflict[K, V]{p.Key, prev_v, p.Val})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError[K, V]{Conflicts: conflicts}
+	}
+	for k, v := range pending {
+		m[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package maps
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestMergeStrict(t *testing.T) {
+	dst := map[string]int{"a": 1, "b": 2}
+	if err := MergeStrict(dst, map[string]int{"b": 2, "c": 3}); err != nil {
+		t.Fatalf("MergeStrict() = %v", err)
+	}
+	if want := map[string]int{"a": 1, "b": 2, "c": 3}; !maps.Equal(dst, want) {
+		t.Errorf("MergeStrict() = %v, want %v", dst, want)
+	}
+
+	err := MergeStrict(dst, map[string]int{"a": 10, "d": 4})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("MergeStrict() = %v, want ErrConflict", err)
+	}
+	if want := map[string]int{"a": 1, "b": 2, "c": 3}; !maps.Equal(dst, want) {
+		t.Errorf("failed MergeStrict() modified dst: %v", dst)
+	}
+	var ce *ConflictError[string, int]
+	if !errors.As(err, &ce) {
+		t.Fatalf("errors.As() failed for %T", err)
+	}
+	if want := []Conflict[string, int]{{"a", 1, 10}}; len(ce.Conflicts) != 1 || ce.Conflicts[0] != want[0] {
+		t.Errorf("Conflicts = %v, want %v", ce.Conflicts, want)
+	}
+	if got, want := err.Error(), "maps: conflicting key a (1 vs 10)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestInsertStrict(t *testing.T) {
+	type pair = Pair[string, int]
+	tests := []struct {
+		name      string
+		pairs     []*pair
+		want      map[string]int
+		conflicts map[string]struct{}
+	}{
+		{"new keys", []*pair{{"b", 2}, {"c", 3}}, map[string]int{"a": 1, "b": 2, "c": 3}, nil},
+		{"same value", []*pair{{"a", 1}}, map[string]int{"a": 1}, nil},
+		{"existing key", []*pair{{"a", 2}, {"b", 2}}, map[string]int{"a": 1}, map[string]struct{}{"a": {}}},
+		{"duplicate pairs", []*pair{{"b", 2}, {"b", 3}}, map[string]int{"a": 1}, map[string]struct{}{"b": {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string]int{"a": 1}
+			err := InsertStrict(m, tt.pairs...)
+			if !maps.Equal(m, tt.want) {
+				t.Errorf("InsertStrict() = %v, want %v", m, tt.want)
+			}
+			if tt.conflicts == nil {
+				if err != nil {
+					t.Errorf("InsertStrict() = %v, want nil", err)
+				}
+				return
+			}
+			var ce *ConflictError[string, int]
+			if !errors.As(err, &ce) {
+				t.Fatalf("InsertStrict() = %v, want *ConflictError", err)
+			}
+			if !maps.Equal(ce.Keys(), tt.conflicts) {
+				t.Errorf("conflicting keys = %v, want %v", ce.Keys(), tt.conflicts)
+			}
+		})
+	}
+}
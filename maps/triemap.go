@@ -0,0 +1,106 @@
+package maps
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/trie"
+)
+
+// TrieMap is a map with string keys backed by a radix tree. Besides the usual
+// lookups and updates, it answers prefix queries: WithPrefix iterates over the
+// keys that start with a prefix without scanning the rest of the map, and
+// LongestPrefix finds the longest key that is a prefix of a string. Keys are
+// always iterated in sorted order.
+//
+// The zero value is an empty map ready to use. A TrieMap is not safe for
+// concurrent use.
+type TrieMap[V any] struct {
+	t trie.Trie[V]
+}
+
+// TrieMapFrom returns a TrieMap constructed from the key-value pairs in m.
+func TrieMapFrom[M ~map[string]V, V any](m M) *TrieMap[V] {
+	r := &TrieMap[V]{}
+	for k, v := range m {
+		r.t.Put(k, v)
+	}
+	return r
+}
+
+// Len returns the number of elements in m.
+func (m *TrieMap[V]) Len() int {
+	return m.t.Len()
+}
+
+// Has checks if there is a key in m.
+func (m *TrieMap[V]) Has(k string) bool {
+	_, ok := m.t.Get(k)
+	return ok
+}
+
+// Get returns the value associated with the key k.
+func (m *TrieMap[V]) Get(k string) (V, bool) {
+	return m.t.Get(k)
+}
+
+// Set associates the value v with the key k. Returns true if k was not in m
+// before.
+func (m *TrieMap[V]) Set(k string, v V) bool {
+	return m.t.Put(k, v)
+}
+
+// Delete removes the key k from m. Returns false if there was no such key.
+func (m *TrieMap[V]) Delete(k string) bool {
+	_, ok := m.t.Delete(k)
+	return ok
+}
+
+// Clear removes all elements from m.
+func (m *TrieMap[V]) Clear() {
+	m.t.Clear()
+}
+
+// WithPrefix returns an iterator over key-value pairs in m whose keys start
+// with prefix, in key order.
+func (m *TrieMap[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return m.t.WithPrefix(prefix)
+}
+
+// LongestPrefix returns the longest key in m that is a prefix of s, together
+// with its value.
+func (m *TrieMap[V]) LongestPrefix(s string) (k string, v V, ok bool) {
+	return m.t.LongestPrefix(s)
+}
+
+// All returns an iterator over key-value pairs in m, in key order.
+func (m *TrieMap[V]) All() iter.Seq2[string, V] {
+	return m.t.All()
+}
+
+// Keys returns the keys of m in order.
+func (m *TrieMap[V]) Keys() []string {
+	r := make([]string, 0, m.Len())
+	for k := range m.t.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Pairs returns a slice of key-value pairs constructed from m in key order.
+// The result is equivalent to what SortedByKey produces for a plain map.
+func (m *TrieMap[V]) Pairs() []*Pair[string, V] {
+	r := make([]*Pair[string, V], 0, m.Len())
+	for k, v := range m.t.All() {
+		r = append(r, &Pair[string, V]{k, v})
+	}
+	return r
+}
+
+// Map returns a plain map constructed from m.
+func (m *TrieMap[V]) Map() map[string]V {
+	r := make(map[string]V, m.Len())
+	for k, v := range m.t.All() {
+		r[k] = v
+	}
+	return r
+}
@@ -0,0 +1,264 @@
+package sets
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Bits is a set of small unsigned integers stored as a bitset. It is much more
+// compact and faster than a map-based set when the keys are dense, such as
+// enumerations or sequential IDs. The memory use is proportional to the
+// largest key, not to the number of keys.
+//
+// Keys can not be larger than MaxBitsKey, which takes 2 MiB. Insert panics
+// on larger keys, the other methods treat them as missing.
+//
+// The zero value is an empty set ready to use.
+type Bits[K constraints.Unsigned] struct {
+	w []uint64
+}
+
+// BitSet is a set of uint values stored as a bitset.
+type BitSet = Bits[uint]
+
+// MaxBitsKey is the largest key that can be inserted into Bits.
+const MaxBitsKey = 1<<24 - 1
+
+// BitsOf returns a bitset containing the keys.
+func BitsOf[K constraints.Unsigned](keys ...K) *Bits[K] {
+	b := &Bits[K]{}
+	b.Insert(keys...)
+	return b
+}
+
+// BitsFrom returns a bitset with the keys of s.
+func BitsFrom[S ~map[K]struct{}, K constraints.Unsigned](s S) *Bits[K] {
+	b := &Bits[K]{}
+	for k := range s {
+		b.Insert(k)
+	}
+	return b
+}
+
+// split returns the index of the word that holds k and the bit of k in it. The
+// index is math.MaxInt for keys larger than MaxBitsKey, so that it is always
+// out of range.
+func split[K constraints.Unsigned](k K) (int, uint64) {
+	if uint64(k) > MaxBitsKey {
+		return math.MaxInt, 0
+	}
+	return int(uint64(k) / 64), 1 << (uint64(k) % 64)
+}
+
+// Count returns the number of keys in b.
+func (b *Bits[K]) Count() int {
+	n := 0
+	for _, w := range b.w {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Contains checks if there is a key in b.
+func (b *Bits[K]) Contains(k K) bool {
+	i, bit := split(k)
+	return i < len(b.w) && b.w[i]&bit != 0
+}
+
+// Insert inserts the keys into b.
+func (b *Bits[K]) Insert(keys ...K) {
+	for _, k := range keys {
+		i, bit := split(k)
+		if i == math.MaxInt {
+			panic(fmt.Sprintf("sets: key %d is larger than MaxBitsKey", k))
+		}
+		if i >= len(b.w) {
+			b.w = append(b.w, make([]uint64, i+1-len(b.w))...)
+		}
+		b.w[i] |= bit
+	}
+}
+
+// Remove removes the keys from b.
+func (b *Bits[K]) Remove(keys ...K) {
+	for _, k := range keys {
+		if i, bit := split(k); i < len(b.w) {
+			b.w[i] &^= bit
+		}
+	}
+	b.trim()
+}
+
+// trim drops the trailing zero words.
+func (b *Bits[K]) trim() {
+	n := len(b.w)
+	for n > 0 && b.w[n-1] == 0 {
+		n--
+	}
+	b.w = b.w[:n]
+}
+
+// Clear removes all keys from b.
+func (b *Bits[K]) Clear() {
+	b.w = b.w[:0]
+}
+
+// Clone returns a copy of b.
+func (b *Bits[K]) Clone() *Bits[K] {
+	return &Bits[K]{w: append([]uint64(nil), b.w...)}
+}
+
+// Equal checks if b and o contain the same keys.
+func (b *Bits[K]) Equal(o *Bits[K]) bool {
+	short, long := b.w, o.w
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset checks if all the keys of b are in o.
+func (b *Bits[K]) IsSubset(o *Bits[K]) bool {
+	for i, w := range b.w {
+		var ow uint64
+		if i < len(o.w) {
+			ow = o.w[i]
+		}
+		if w&^ow != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new bitset with the keys from both b and o.
+func (b *Bits[K]) Union(o *Bits[K]) *Bits[K] {
+	r := b.Clone()
+	r.Merge(o)
+	return r
+}
+
+// Intersection returns a new bitset with the keys that are both in b and o.
+func (b *Bits[K]) Intersection(o *Bits[K]) *Bits[K] {
+	r := b.Clone()
+	r.Intersect(o)
+	return r
+}
+
+// Difference returns a new bitset with the keys from b that are not in o.
+func (b *Bits[K]) Difference(o *Bits[K]) *Bits[K] {
+	r := b.Clone()
+	r.Subtract(o)
+	return r
+}
+
+// SymmetricDifference returns a new bitset with the keys that are either in b
+// or in o, but not in both.
+func (b *Bits[K]) SymmetricDifference(o *Bits[K]) *Bits[K] {
+	r := b.Clone()
+	r.SymmetricSubtract(o)
+	return r
+}
+
+// Merge inserts all the keys of o into b.
+func (b *Bits[K]) Merge(o *Bits[K]) {
+	if len(o.w) > len(b.w) {
+		b.w = append(b.w, make([]uint64, len(o.w)-len(b.w))...)
+	}
+	for i, w := range o.w {
+		b.w[i] |= w
+	}
+}
+
+// Intersect removes the keys of b that are not in o.
+func (b *Bits[K]) Intersect(o *Bits[K]) {
+	if len(b.w) > len(o.w) {
+		b.w = b.w[:len(o.w)]
+	}
+	for i := range b.w {
+		b.w[i] &= o.w[i]
+	}
+	b.trim()
+}
+
+// Subtract removes the keys of o from b.
+func (b *Bits[K]) Subtract(o *Bits[K]) {
+	for i := range min(len(b.w), len(o.w)) {
+		b.w[i] &^= o.w[i]
+	}
+	b.trim()
+}
+
+// SymmetricSubtract removes the keys of o that are in b from b, and inserts
+// the rest of the keys of o into b.
+func (b *Bits[K]) SymmetricSubtract(o *Bits[K]) {
+	if len(o.w) > len(b.w) {
+		b.w = append(b.w, make([]uint64, len(o.w)-len(b.w))...)
+	}
+	for i, w := range o.w {
+		b.w[i] ^= w
+	}
+	b.trim()
+}
+
+// NextSet returns the smallest key in b that is greater than or equal to k.
+func (b *Bits[K]) NextSet(k K) (K, bool) {
+	i, bit := split(k)
+	if i >= len(b.w) {
+		return 0, false
+	}
+	w := b.w[i] &^ (bit - 1)
+	for w == 0 {
+		i++
+		if i == len(b.w) {
+			return 0, false
+		}
+		w = b.w[i]
+	}
+	return K(uint64(i)*64 + uint64(bits.TrailingZeros64(w))), true
+}
+
+// All returns an iterator over the keys of b in ascending order.
+func (b *Bits[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for i := 0; i < len(b.w); i++ {
+			for w := b.w[i]; w != 0; w &= w - 1 {
+				if !yield(K(uint64(i)*64 + uint64(bits.TrailingZeros64(w)))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns the keys of b as a slice in ascending order.
+func (b *Bits[K]) Keys() []K {
+	r := make([]K, 0, b.Count())
+	for k := range b.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Set returns the keys of b as a map-based set.
+func (b *Bits[K]) Set() Set[K] {
+	r := make(Set[K], b.Count())
+	for k := range b.All() {
+		r[k] = struct{}{}
+	}
+	return r
+}
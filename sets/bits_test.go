@@ -0,0 +1,122 @@
+package sets
+
+import (
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestBits(t *testing.T) {
+	var b BitSet
+	if b.Count() != 0 || b.Contains(0) {
+		t.Fatalf("zero value is not empty")
+	}
+	b.Insert(0, 63, 64, 200)
+	if got := b.Keys(); !slices.Equal(got, []uint{0, 63, 64, 200}) {
+		t.Errorf("Keys() = %v", got)
+	}
+	if !b.Contains(63) || b.Contains(62) || b.Contains(1000) {
+		t.Errorf("Contains() is wrong")
+	}
+	b.Remove(200, 1000)
+	if b.Count() != 3 || !b.Equal(BitsOf[uint](0, 63, 64)) {
+		t.Errorf("Remove() left %v", b.Keys())
+	}
+	c := b.Clone()
+	c.Insert(5)
+	if b.Contains(5) {
+		t.Errorf("Clone() shares storage")
+	}
+	b.Clear()
+	if b.Count() != 0 || !b.Equal(&BitSet{}) {
+		t.Errorf("Clear() left %v", b.Keys())
+	}
+}
+
+func TestBitsMaxKey(t *testing.T) {
+	var b Bits[uint64]
+	b.Insert(MaxBitsKey)
+	if !b.Contains(MaxBitsKey) || b.Count() != 1 {
+		t.Errorf("MaxBitsKey was not inserted")
+	}
+	if k, ok := b.NextSet(0); !ok || k != MaxBitsKey {
+		t.Errorf("NextSet(0) = %d, %v", k, ok)
+	}
+	if b.Contains(MaxBitsKey+1) || b.Contains(^uint64(0)) {
+		t.Errorf("Contains() = true for a key larger than MaxBitsKey")
+	}
+	if _, ok := b.NextSet(MaxBitsKey + 1); ok {
+		t.Errorf("NextSet() = _, true after MaxBitsKey")
+	}
+	b.Remove(^uint64(0))
+	b.Clear()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Insert() did not panic for a key larger than MaxBitsKey")
+		}
+		if len(b.w) != 0 {
+			t.Errorf("Insert() grew the set to %d words", len(b.w))
+		}
+	}()
+	b.Insert(MaxBitsKey + 1)
+}
+
+func TestBitsNextSet(t *testing.T) {
+	b := BitsOf[uint8](3, 70, 130)
+	tests := []struct {
+		from uint8
+		want uint8
+		ok   bool
+	}{
+		{0, 3, true},
+		{3, 3, true},
+		{4, 70, true},
+		{71, 130, true},
+		{131, 0, false},
+		{255, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := b.NextSet(tt.from); got != tt.want || ok != tt.ok {
+			t.Errorf("NextSet(%d) = %d, %v, want %d, %v", tt.from, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBitsAlgebra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() map[uint16]struct{} {
+		s := map[uint16]struct{}{}
+		for i := rnd.Intn(100); i > 0; i-- {
+			s[uint16(rnd.Intn(500))] = struct{}{}
+		}
+		return s
+	}
+	for i := 0; i < 100; i++ {
+		s1, s2 := random(), random()
+		b1, b2 := BitsFrom(s1), BitsFrom(s2)
+		if !Equal(b1.Set(), s1) {
+			t.Fatalf("Set() = %s, want %s", to_string(b1.Set()), to_string(s1))
+		}
+		check := func(name string, got *Bits[uint16], want map[uint16]struct{}) {
+			t.Helper()
+			if !Equal(got.Set(), want) || got.Count() != len(want) {
+				t.Fatalf("%s() = %s, want %s", name, to_string(got.Set()), to_string(want))
+			}
+		}
+		check("Union", b1.Union(b2), Union(s1, s2))
+		check("Intersection", b1.Intersection(b2), Intersection(s1, s2))
+		check("Difference", b1.Difference(b2), Difference(s1, s2))
+		check("SymmetricDifference", b1.SymmetricDifference(b2), SymmetricDifference(s1, s2))
+		if got, want := b1.IsSubset(b2), IsSubset(s1, s2); got != want {
+			t.Fatalf("IsSubset() = %v, want %v", got, want)
+		}
+		if got := b1.Intersection(b2); !got.IsSubset(b1) || !got.Equal(b2.Intersection(b1)) {
+			t.Fatalf("Intersection() is not commutative")
+		}
+		if !Equal(b1.Set(), s1) || !Equal(b2.Set(), s2) {
+			t.Fatalf("operands were modified")
+		}
+	}
+}
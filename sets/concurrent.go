@@ -0,0 +1,264 @@
+package sets
+
+import (
+	"hash/maphash"
+	"runtime"
+	"sync"
+)
+
+// Concurrent is a set that is safe for concurrent use by multiple goroutines.
+// All operations are guarded by a single sync.RWMutex, which suits
+// read-mostly workloads. For write-heavy workloads, consider Sharded.
+//
+// The zero value is an empty set ready to use. A Concurrent must not be copied
+// after first use.
+type Concurrent[K comparable] struct {
+	mu sync.RWMutex
+	s  Set[K]
+}
+
+// NewConcurrent returns a concurrent set containing the keys.
+func NewConcurrent[K comparable](keys ...K) *Concurrent[K] {
+	return &Concurrent[K]{s: Of(keys...)}
+}
+
+// Len returns the number of keys in c.
+func (c *Concurrent[K]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.s)
+}
+
+// Contains checks if there is a key in c.
+func (c *Concurrent[K]) Contains(k K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Contains(c.s, k)
+}
+
+// ContainsAny checks if any of the keys is in c.
+func (c *Concurrent[K]) ContainsAny(keys ...K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return ContainsAny(c.s, keys...)
+}
+
+// ContainsAll checks if all the keys are in c.
+func (c *Concurrent[K]) ContainsAll(keys ...K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return ContainsAll(c.s, keys...)
+}
+
+// Insert inserts the keys into c.
+func (c *Concurrent[K]) Insert(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.s == nil {
+		c.s = make(Set[K], len(keys))
+	}
+	Insert(c.s, keys...)
+}
+
+// InsertIfAbsent atomically inserts the key into c. Returns false if the key
+// was already there.
+func (c *Concurrent[K]) InsertIfAbsent(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if Contains(c.s, k) {
+		return false
+	}
+	if c.s == nil {
+		c.s = Set[K]{}
+	}
+	c.s[k] = struct{}{}
+	return true
+}
+
+// Remove removes the keys from c.
+func (c *Concurrent[K]) Remove(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	Remove(c.s, keys...)
+}
+
+// RemoveIfPresent atomically removes the key from c. Returns false if there
+// was no such key.
+func (c *Concurrent[K]) RemoveIfPresent(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !Contains(c.s, k) {
+		return false
+	}
+	delete(c.s, k)
+	return true
+}
+
+// Clear removes all keys from c.
+func (c *Concurrent[K]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	Clear(c.s)
+}
+
+// Keys returns the keys from c as a slice. The keys will be in an
+// indeterminate order.
+func (c *Concurrent[K]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Keys(c.s)
+}
+
+// Snapshot returns a copy of c as a plain set that can be used with the rest
+// of this package.
+func (c *Concurrent[K]) Snapshot() Set[K] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Clone(c.s)
+}
+
+// Sharded is a set that is safe for concurrent use by multiple goroutines.
+// The keys are distributed among a number of independently locked shards by
+// their hash, which reduces lock contention in write-heavy workloads.
+//
+// Operations on a single key are atomic. ContainsAny and ContainsAll check
+// each key atomically, but not all of them at once. Len, Keys and Snapshot
+// lock all the shards and observe a consistent state.
+//
+// Use NewSharded to create a Sharded set.
+type Sharded[K comparable] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []shard[K]
+}
+
+type shard[K comparable] struct {
+	Concurrent[K]
+	_ [64]byte // avoid false sharing between neighbouring shards
+}
+
+// NewSharded returns an empty set with n shards. The number of shards is
+// rounded up to a power of two; if n <= 0, it is chosen based on
+// runtime.GOMAXPROCS.
+func NewSharded[K comparable](n int) *Sharded[K] {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	return &Sharded[K]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(size - 1),
+		shards: make([]shard[K], size),
+	}
+}
+
+func (s *Sharded[K]) shard(k K) *Concurrent[K] {
+	return &s.shards[maphash.Comparable(s.seed, k)&s.mask].Concurrent
+}
+
+// lockAll read-locks all the shards, the returned function unlocks them.
+func (s *Sharded[K]) lockAll() (unlock func()) {
+	for i := range s.shards {
+		s.shards[i].mu.RLock()
+	}
+	return func() {
+		for i := range s.shards {
+			s.shards[i].mu.RUnlock()
+		}
+	}
+}
+
+// Len returns the number of keys in s.
+func (s *Sharded[K]) Len() int {
+	defer s.lockAll()()
+	n := 0
+	for i := range s.shards {
+		n += len(s.shards[i].s)
+	}
+	return n
+}
+
+// Contains checks if there is a key in s.
+func (s *Sharded[K]) Contains(k K) bool {
+	return s.shard(k).Contains(k)
+}
+
+// ContainsAny checks if any of the keys is in s.
+func (s *Sharded[K]) ContainsAny(keys ...K) bool {
+	for _, k := range keys {
+		if s.shard(k).Contains(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll checks if all the keys are in s.
+func (s *Sharded[K]) ContainsAll(keys ...K) bool {
+	for _, k := range keys {
+		if !s.shard(k).Contains(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// Insert inserts the keys into s.
+func (s *Sharded[K]) Insert(keys ...K) {
+	for _, k := range keys {
+		s.shard(k).Insert(k)
+	}
+}
+
+// InsertIfAbsent atomically inserts the key into s. Returns false if the key
+// was already there.
+func (s *Sharded[K]) InsertIfAbsent(k K) bool {
+	return s.shard(k).InsertIfAbsent(k)
+}
+
+// Remove removes the keys from s.
+func (s *Sharded[K]) Remove(keys ...K) {
+	for _, k := range keys {
+		s.shard(k).Remove(k)
+	}
+}
+
+// RemoveIfPresent atomically removes the key from s. Returns false if there
+// was no such key.
+func (s *Sharded[K]) RemoveIfPresent(k K) bool {
+	return s.shard(k).RemoveIfPresent(k)
+}
+
+// Clear removes all keys from s.
+func (s *Sharded[K]) Clear() {
+	for i := range s.shards {
+		s.shards[i].Clear()
+	}
+}
+
+// Keys returns the keys from s as a slice. The keys will be in an
+// indeterminate order.
+func (s *Sharded[K]) Keys() []K {
+	defer s.lockAll()()
+	var r []K
+	for i := range s.shards {
+		for k := range s.shards[i].s {
+			r = append(r, k)
+		}
+	}
+	return r
+}
+
+// Snapshot returns a copy of s as a plain set that can be used with the rest
+// of this package.
+func (s *Sharded[K]) Snapshot() Set[K] {
+	defer s.lockAll()()
+	r := Set[K]{}
+	for i := range s.shards {
+		Merge(r, s.shards[i].s)
+	}
+	return r
+}
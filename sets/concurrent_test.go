@@ -0,0 +1,88 @@
+package sets
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type concurrentSet interface {
+	Len() int
+	Contains(k int) bool
+	ContainsAny(keys ...int) bool
+	ContainsAll(keys ...int) bool
+	Insert(keys ...int)
+	InsertIfAbsent(k int) bool
+	Remove(keys ...int)
+	RemoveIfPresent(k int) bool
+	Keys() []int
+	Snapshot() Set[int]
+}
+
+func concurrentSets() map[string]func() concurrentSet {
+	return map[string]func() concurrentSet{
+		"Concurrent": func() concurrentSet { return &Concurrent[int]{} },
+		"Sharded":    func() concurrentSet { return NewSharded[int](8) },
+	}
+}
+
+func TestConcurrentSetAPI(t *testing.T) {
+	for name, create := range concurrentSets() {
+		t.Run(name, func(t *testing.T) {
+			s := create()
+			s.Insert(1, 2, 3)
+			if !s.InsertIfAbsent(4) || s.InsertIfAbsent(4) {
+				t.Errorf("InsertIfAbsent() did not report the existing key")
+			}
+			s.Remove(1)
+			if !s.RemoveIfPresent(2) || s.RemoveIfPresent(2) {
+				t.Errorf("RemoveIfPresent() did not report the missing key")
+			}
+			if !s.Contains(3) || s.Contains(1) {
+				t.Errorf("Contains() reported wrong membership")
+			}
+			if !s.ContainsAny(1, 3) || s.ContainsAny(1, 2) {
+				t.Errorf("ContainsAny() reported wrong membership")
+			}
+			if !s.ContainsAll(3, 4) || s.ContainsAll(3, 5) {
+				t.Errorf("ContainsAll() reported wrong membership")
+			}
+			if want := set(3, 4); !Equal(s.Snapshot(), want) || s.Len() != 2 || len(s.Keys()) != 2 {
+				t.Errorf("Snapshot() = %v, want %s", s.Keys(), to_string(want))
+			}
+		})
+	}
+}
+
+func TestConcurrentSetParallel(t *testing.T) {
+	const workers, keys = 8, 1000
+	for name, create := range concurrentSets() {
+		t.Run(name, func(t *testing.T) {
+			s := create()
+			var inserted atomic.Int64
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for k := 0; k < keys; k++ {
+						if s.InsertIfAbsent(k) {
+							inserted.Add(1)
+						}
+						s.Contains(k)
+						if k%10 == 0 {
+							s.Snapshot()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			if inserted.Load() != keys {
+				t.Errorf("InsertIfAbsent() succeeded %d times, want %d", inserted.Load(), keys)
+			}
+			if s.Len() != keys {
+				t.Errorf("Len() = %d, want %d", s.Len(), keys)
+			}
+		})
+	}
+}
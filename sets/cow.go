@@ -0,0 +1,67 @@
+package sets
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWrite is a set for read-mostly workloads that is safe for concurrent
+// use. Readers never lock: Load returns the current version of the set with a
+// single atomic operation. Writers are serialized; each Update works on a
+// private copy which is then published atomically, so readers observe either
+// all or none of the changes made by an Update.
+//
+// The zero value is an empty set ready to use. A CopyOnWrite must not be
+// copied after first use.
+type CopyOnWrite[K comparable] struct {
+	mu sync.Mutex // serializes writers
+	p  atomic.Pointer[Set[K]]
+}
+
+// NewCopyOnWrite returns a copy-on-write set containing the keys.
+func NewCopyOnWrite[K comparable](keys ...K) *CopyOnWrite[K] {
+	c := &CopyOnWrite[K]{}
+	s := Of(keys...)
+	c.p.Store(&s)
+	return c
+}
+
+// Load returns the current version of the set. The result is a read-only
+// view: it can be used with all the functions of this package that do not
+// modify their arguments, but it must not be modified itself.
+func (c *CopyOnWrite[K]) Load() Set[K] {
+	if p := c.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Contains checks if there is a key in the current version of the set.
+func (c *CopyOnWrite[K]) Contains(k K) bool {
+	return Contains(c.Load(), k)
+}
+
+// Len returns the number of keys in the current version of the set.
+func (c *CopyOnWrite[K]) Len() int {
+	return len(c.Load())
+}
+
+// Update calls fn with a private copy of the current set and then publishes
+// the copy as the new version. Batching several changes in one Update makes
+// them visible to readers at once. Updates are serialized, fn must not call
+// Update or Store on c.
+func (c *CopyOnWrite[K]) Update(fn func(s Set[K])) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next := Clone(c.Load())
+	fn(next)
+	c.p.Store(&next)
+}
+
+// Store publishes s as the new version of the set. c takes the ownership of
+// s: it must not be modified after the call.
+func (c *CopyOnWrite[K]) Store(s Set[K]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.Store(&s)
+}
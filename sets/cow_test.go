@@ -0,0 +1,60 @@
+package sets
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCopyOnWrite(t *testing.T) {
+	var c CopyOnWrite[int]
+	if c.Len() != 0 || c.Contains(1) {
+		t.Fatalf("zero value is not empty")
+	}
+
+	c.Update(func(s Set[int]) {
+		s.Add(1, 2, 3)
+	})
+	before := c.Load()
+	c.Update(func(s Set[int]) {
+		s.Remove(1)
+		s.Add(4)
+	})
+
+	if !Equal(before, set(1, 2, 3)) {
+		t.Errorf("Update() modified a published version: %s", to_string(before))
+	}
+	if got := c.Load(); !Equal(got, set(2, 3, 4)) {
+		t.Errorf("Load() = %s, want %s", to_string(got), to_string(set(2, 3, 4)))
+	}
+	if !IsSubset(set(2, 3), c.Load()) {
+		t.Errorf("IsSubset() = false for the loaded view")
+	}
+}
+
+func TestCopyOnWriteParallel(t *testing.T) {
+	c := NewCopyOnWrite(0)
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// every published version holds a contiguous range of keys
+				s := c.Load()
+				if !Contains(s, len(s)-1) {
+					t.Errorf("inconsistent version of %d keys", len(s))
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i < 100; i++ {
+		c.Update(func(s Set[int]) {
+			s.Add(len(s))
+		})
+	}
+	wg.Wait()
+	if c.Len() != 100 {
+		t.Errorf("Len() = %d, want 100", c.Len())
+	}
+}
@@ -0,0 +1,106 @@
+package sets
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/hamt"
+)
+
+// ImmutableSet is a persistent set: it is never modified in place. With and
+// Without return new versions in O(log n) that share most of their structure
+// with the original, so keeping old versions around is cheap. ImmutableSet
+// values are safe for concurrent use without locking.
+//
+// Union, Difference and Intersection skip the subtrees that are shared by
+// their operands, which makes them fast for versions derived from a common
+// ancestor.
+//
+// The zero value is an empty set ready to use.
+type ImmutableSet[K comparable] struct {
+	m hamt.Map[K, struct{}]
+}
+
+// ImmutableSetOf returns an immutable set containing the keys.
+func ImmutableSetOf[K comparable](keys ...K) ImmutableSet[K] {
+	var r hamt.Map[K, struct{}]
+	for _, k := range keys {
+		r = r.With(k, struct{}{})
+	}
+	return ImmutableSet[K]{r}
+}
+
+// ImmutableSetFrom returns an immutable set with the keys of s.
+func ImmutableSetFrom[S ~map[K]struct{}, K comparable](s S) ImmutableSet[K] {
+	var r hamt.Map[K, struct{}]
+	for k := range s {
+		r = r.With(k, struct{}{})
+	}
+	return ImmutableSet[K]{r}
+}
+
+// Len returns the number of keys in s.
+func (s ImmutableSet[K]) Len() int {
+	return s.m.Len()
+}
+
+// Contains checks if there is a key in s.
+func (s ImmutableSet[K]) Contains(k K) bool {
+	_, ok := s.m.Get(k)
+	return ok
+}
+
+// With returns a version of s with the keys inserted.
+func (s ImmutableSet[K]) With(keys ...K) ImmutableSet[K] {
+	r := s.m
+	for _, k := range keys {
+		if _, ok := r.Get(k); !ok {
+			r = r.With(k, struct{}{})
+		}
+	}
+	return ImmutableSet[K]{r}
+}
+
+// Without returns a version of s with the keys removed.
+func (s ImmutableSet[K]) Without(keys ...K) ImmutableSet[K] {
+	r := s.m
+	for _, k := range keys {
+		r = r.Without(k)
+	}
+	return ImmutableSet[K]{r}
+}
+
+// Union returns a set with the keys from both s and o.
+func (s ImmutableSet[K]) Union(o ImmutableSet[K]) ImmutableSet[K] {
+	return ImmutableSet[K]{s.m.Union(o.m)}
+}
+
+// Difference returns a set with the keys from s that are not in o.
+func (s ImmutableSet[K]) Difference(o ImmutableSet[K]) ImmutableSet[K] {
+	return ImmutableSet[K]{s.m.Difference(o.m)}
+}
+
+// Intersection returns a set with the keys that are both in s and o.
+func (s ImmutableSet[K]) Intersection(o ImmutableSet[K]) ImmutableSet[K] {
+	return ImmutableSet[K]{s.m.Intersection(o.m)}
+}
+
+// All returns an iterator over the keys of s. The iteration order is
+// indeterminate.
+func (s ImmutableSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s.m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Set returns the keys of s as a plain set.
+func (s ImmutableSet[K]) Set() Set[K] {
+	r := make(Set[K], s.m.Len())
+	for k := range s.m.All() {
+		r[k] = struct{}{}
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package sets
+
+import "testing"
+
+func TestImmutableSet(t *testing.T) {
+	var zero ImmutableSet[int]
+	if zero.Len() != 0 || zero.Contains(1) {
+		t.Fatalf("zero value is not empty")
+	}
+
+	v1 := ImmutableSetOf(1, 2, 3)
+	v2 := v1.With(4, 5).Without(1)
+	if !Equal(v1.Set(), set(1, 2, 3)) {
+		t.Errorf("With() modified the original: %s", to_string(v1.Set()))
+	}
+	if !Equal(v2.Set(), set(2, 3, 4, 5)) {
+		t.Errorf("v2 = %s, want %s", to_string(v2.Set()), to_string(set(2, 3, 4, 5)))
+	}
+	if got := v2.With(2, 3); got.Len() != 4 {
+		t.Errorf("With() of existing keys changed the size to %d", got.Len())
+	}
+	if !ImmutableSetFrom(set(7, 8)).Contains(8) {
+		t.Errorf("ImmutableSetFrom() lost a key")
+	}
+
+	tests := []struct {
+		name string
+		op   func(a, b ImmutableSet[int]) ImmutableSet[int]
+		want Set[int]
+	}{
+		{"Union", ImmutableSet[int].Union, set(1, 2, 3, 4, 5)},
+		{"Difference", ImmutableSet[int].Difference, set(1)},
+		{"Intersection", ImmutableSet[int].Intersection, set(2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op(v1, v2).Set(); !Equal(got, tt.want) {
+				t.Errorf("%s() = %s, want %s", tt.name, to_string(got), to_string(tt.want))
+			}
+		})
+	}
+
+	n := 0
+	for k := range v2.All() {
+		if !v2.Contains(k) {
+			t.Errorf("All() yielded a missing key %d", k)
+		}
+		n++
+	}
+	if n != v2.Len() {
+		t.Errorf("All() yielded %d keys, want %d", n, v2.Len())
+	}
+}
@@ -0,0 +1,226 @@
+package sets
+
+import (
+	"iter"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Interval is a half-open interval [Lo, Hi). It is empty if Lo >= Hi.
+type Interval[T constraints.Ordered] struct {
+	Lo, Hi T
+}
+
+// Empty checks if there are no values in iv.
+func (iv Interval[T]) Empty() bool {
+	return !(iv.Lo < iv.Hi)
+}
+
+// Contains checks if x is in iv.
+func (iv Interval[T]) Contains(x T) bool {
+	return iv.Lo <= x && x < iv.Hi
+}
+
+// IntervalSet is a set of ordered values stored as a sorted list of disjoint
+// half-open intervals. Overlapping and adjacent intervals are coalesced, so
+// the memory use depends on the number of ranges, not on the number of values
+// they cover. This suits port ranges, address ranges, time windows and
+// similar data.
+//
+// The zero value is an empty set ready to use.
+type IntervalSet[T constraints.Ordered] struct {
+	ivs []Interval[T] // sorted, non-empty, neither overlapping nor adjacent
+}
+
+// IntervalSetOf returns a set containing the intervals.
+func IntervalSetOf[T constraints.Ordered](ivs ...Interval[T]) *IntervalSet[T] {
+	s := &IntervalSet[T]{}
+	for _, iv := range ivs {
+		s.Add(iv.Lo, iv.Hi)
+	}
+	return s
+}
+
+// Len returns the number of disjoint intervals in s.
+func (s *IntervalSet[T]) Len() int {
+	return len(s.ivs)
+}
+
+// Empty checks if there are no values in s.
+func (s *IntervalSet[T]) Empty() bool {
+	return len(s.ivs) == 0
+}
+
+// search returns the index of the first interval that ends after x.
+func (s *IntervalSet[T]) search(x T) int {
+	return sort.Search(len(s.ivs), func(i int) bool { return x < s.ivs[i].Hi })
+}
+
+// Contains checks if x is in s.
+func (s *IntervalSet[T]) Contains(x T) bool {
+	i := s.search(x)
+	return i < len(s.ivs) && s.ivs[i].Lo <= x
+}
+
+// ContainsRange checks if all the values in [lo, hi) are in s.
+func (s *IntervalSet[T]) ContainsRange(lo, hi T) bool {
+	if !(lo < hi) {
+		return true
+	}
+	i := s.search(lo)
+	return i < len(s.ivs) && s.ivs[i].Lo <= lo && hi <= s.ivs[i].Hi
+}
+
+// Add inserts the values in [lo, hi) into s.
+func (s *IntervalSet[T]) Add(lo, hi T) {
+	if !(lo < hi) {
+		return
+	}
+	// intervals i..j-1 overlap or touch [lo, hi)
+	i := sort.Search(len(s.ivs), func(i int) bool { return lo <= s.ivs[i].Hi })
+	j := sort.Search(len(s.ivs), func(i int) bool { return hi < s.ivs[i].Lo })
+	if i < j {
+		lo = min(lo, s.ivs[i].Lo)
+		hi = max(hi, s.ivs[j-1].Hi)
+	}
+	s.ivs = slices.Insert(slices.Delete(s.ivs, i, j), i, Interval[T]{lo, hi})
+}
+
+// Remove removes the values in [lo, hi) from s.
+func (s *IntervalSet[T]) Remove(lo, hi T) {
+	if !(lo < hi) {
+		return
+	}
+	// intervals i..j-1 overlap [lo, hi)
+	i := s.search(lo)
+	j := sort.Search(len(s.ivs), func(i int) bool { return hi <= s.ivs[i].Lo })
+	if i >= j {
+		return
+	}
+	var rest []Interval[T]
+	if s.ivs[i].Lo < lo {
+		rest = append(rest, Interval[T]{s.ivs[i].Lo, lo})
+	}
+	if hi < s.ivs[j-1].Hi {
+		rest = append(rest, Interval[T]{hi, s.ivs[j-1].Hi})
+	}
+	s.ivs = slices.Insert(slices.Delete(s.ivs, i, j), i, rest...)
+}
+
+// Clear removes all values from s.
+func (s *IntervalSet[T]) Clear() {
+	s.ivs = nil
+}
+
+// Clone returns a copy of s.
+func (s *IntervalSet[T]) Clone() *IntervalSet[T] {
+	return &IntervalSet[T]{slices.Clone(s.ivs)}
+}
+
+// Equal checks if s and o contain the same values.
+func (s *IntervalSet[T]) Equal(o *IntervalSet[T]) bool {
+	return slices.Equal(s.ivs, o.ivs)
+}
+
+// Union returns a new set with the values from both s and o.
+func (s *IntervalSet[T]) Union(o *IntervalSet[T]) *IntervalSet[T] {
+	r := &IntervalSet[T]{make([]Interval[T], 0, len(s.ivs)+len(o.ivs))}
+	add := func(iv Interval[T]) {
+		if n := len(r.ivs); n > 0 && iv.Lo <= r.ivs[n-1].Hi {
+			r.ivs[n-1].Hi = max(r.ivs[n-1].Hi, iv.Hi)
+		} else {
+			r.ivs = append(r.ivs, iv)
+		}
+	}
+	i, j := 0, 0
+	for i < len(s.ivs) && j < len(o.ivs) {
+		if s.ivs[i].Lo <= o.ivs[j].Lo {
+			add(s.ivs[i])
+			i++
+		} else {
+			add(o.ivs[j])
+			j++
+		}
+	}
+	for ; i < len(s.ivs); i++ {
+		add(s.ivs[i])
+	}
+	for ; j < len(o.ivs); j++ {
+		add(o.ivs[j])
+	}
+	return r
+}
+
+// Intersection returns a new set with the values that are both in s and o.
+func (s *IntervalSet[T]) Intersection(o *IntervalSet[T]) *IntervalSet[T] {
+	r := &IntervalSet[T]{}
+	for i, j := 0, 0; i < len(s.ivs) && j < len(o.ivs); {
+		lo, hi := max(s.ivs[i].Lo, o.ivs[j].Lo), min(s.ivs[i].Hi, o.ivs[j].Hi)
+		if lo < hi {
+			r.ivs = append(r.ivs, Interval[T]{lo, hi})
+		}
+		if s.ivs[i].Hi < o.ivs[j].Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return r
+}
+
+// Difference returns a new set with the values from s that are not in o.
+func (s *IntervalSet[T]) Difference(o *IntervalSet[T]) *IntervalSet[T] {
+	r := &IntervalSet[T]{}
+	j := 0
+	for _, iv := range s.ivs {
+		lo := iv.Lo
+		for j < len(o.ivs) && o.ivs[j].Hi <= lo {
+			j++
+		}
+		for k := j; k < len(o.ivs) && o.ivs[k].Lo < iv.Hi; k++ {
+			if lo < o.ivs[k].Lo {
+				r.ivs = append(r.ivs, Interval[T]{lo, o.ivs[k].Lo})
+			}
+			lo = max(lo, o.ivs[k].Hi)
+		}
+		if lo < iv.Hi {
+			r.ivs = append(r.ivs, Interval[T]{lo, iv.Hi})
+		}
+	}
+	return r
+}
+
+// Complement returns a new set with the values in [lo, hi) that are not in s.
+func (s *IntervalSet[T]) Complement(lo, hi T) *IntervalSet[T] {
+	bounds := &IntervalSet[T]{}
+	bounds.Add(lo, hi)
+	return bounds.Difference(s)
+}
+
+// Intervals returns an iterator over the disjoint intervals of s in ascending
+// order.
+func (s *IntervalSet[T]) Intervals() iter.Seq[Interval[T]] {
+	return func(yield func(Interval[T]) bool) {
+		for _, iv := range s.ivs {
+			if !yield(iv) {
+				return
+			}
+		}
+	}
+}
+
+// Points returns an iterator over the individual values of an integer interval
+// set in ascending order.
+func Points[T constraints.Integer](s *IntervalSet[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, iv := range s.ivs {
+			for x := iv.Lo; x < iv.Hi; x++ {
+				if !yield(x) {
+					return
+				}
+			}
+		}
+	}
+}
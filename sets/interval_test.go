@@ -0,0 +1,122 @@
+package sets
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+func iv(lo, hi int) Interval[int] {
+	return Interval[int]{lo, hi}
+}
+
+func intervals[T int | time.Duration](s *IntervalSet[T]) []Interval[T] {
+	var r []Interval[T]
+	for iv := range s.Intervals() {
+		r = append(r, iv)
+	}
+	return r
+}
+
+func TestIntervalSetAddRemove(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(s *IntervalSet[int])
+		want []Interval[int]
+	}{
+		{"disjoint", func(s *IntervalSet[int]) { s.Add(20, 30) }, []Interval[int]{iv(0, 10), iv(20, 30), iv(40, 50)}},
+		{"adjacent", func(s *IntervalSet[int]) { s.Add(10, 12) }, []Interval[int]{iv(0, 12), iv(40, 50)}},
+		{"bridge", func(s *IntervalSet[int]) { s.Add(10, 40) }, []Interval[int]{iv(0, 50)}},
+		{"cover", func(s *IntervalSet[int]) { s.Add(-5, 60) }, []Interval[int]{iv(-5, 60)}},
+		{"inside", func(s *IntervalSet[int]) { s.Add(2, 8) }, []Interval[int]{iv(0, 10), iv(40, 50)}},
+		{"empty range", func(s *IntervalSet[int]) { s.Add(20, 20) }, []Interval[int]{iv(0, 10), iv(40, 50)}},
+		{"split", func(s *IntervalSet[int]) { s.Remove(3, 5) }, []Interval[int]{iv(0, 3), iv(5, 10), iv(40, 50)}},
+		{"trim", func(s *IntervalSet[int]) { s.Remove(5, 45) }, []Interval[int]{iv(0, 5), iv(45, 50)}},
+		{"remove all", func(s *IntervalSet[int]) { s.Remove(0, 50) }, nil},
+		{"remove gap", func(s *IntervalSet[int]) { s.Remove(10, 40) }, []Interval[int]{iv(0, 10), iv(40, 50)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := IntervalSetOf(iv(40, 50), iv(0, 10))
+			tt.op(s)
+			if got := intervals(s); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalSetContains(t *testing.T) {
+	s := IntervalSetOf(iv(0, 10), iv(20, 30))
+	for x, want := range map[int]bool{-1: false, 0: true, 9: true, 10: false, 15: false, 20: true, 30: false} {
+		if got := s.Contains(x); got != want {
+			t.Errorf("Contains(%d) = %v", x, got)
+		}
+	}
+	if !s.ContainsRange(2, 10) || s.ContainsRange(5, 25) || !s.ContainsRange(15, 15) {
+		t.Errorf("ContainsRange() is wrong")
+	}
+
+	var windows IntervalSet[time.Duration]
+	windows.Add(time.Hour, 2*time.Hour)
+	windows.Add(2*time.Hour, 3*time.Hour)
+	if got := intervals(&windows); len(got) != 1 || !got[0].Contains(150*time.Minute) {
+		t.Errorf("adjacent windows were not coalesced: %v", got)
+	}
+}
+
+func TestIntervalSetAlgebra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() (*IntervalSet[int], map[int]struct{}) {
+		s, m := &IntervalSet[int]{}, map[int]struct{}{}
+		for i := rnd.Intn(8); i > 0; i-- {
+			lo := rnd.Intn(100)
+			hi := lo + rnd.Intn(20)
+			if rnd.Intn(3) == 0 {
+				s.Remove(lo, hi)
+				for x := lo; x < hi; x++ {
+					delete(m, x)
+				}
+			} else {
+				s.Add(lo, hi)
+				for x := lo; x < hi; x++ {
+					m[x] = struct{}{}
+				}
+			}
+		}
+		return s, m
+	}
+	points := func(s *IntervalSet[int]) map[int]struct{} {
+		r := map[int]struct{}{}
+		for x := range Points(s) {
+			r[x] = struct{}{}
+		}
+		return r
+	}
+	for i := 0; i < 500; i++ {
+		s1, m1 := random()
+		s2, m2 := random()
+		if !Equal(points(s1), m1) {
+			t.Fatalf("%v does not match %s", intervals(s1), to_string(m1))
+		}
+		for name, tt := range map[string]struct {
+			got  *IntervalSet[int]
+			want map[int]struct{}
+		}{
+			"Union":        {s1.Union(s2), Union(m1, m2)},
+			"Intersection": {s1.Intersection(s2), Intersection(m1, m2)},
+			"Difference":   {s1.Difference(s2), Difference(m1, m2)},
+			"Complement":   {s1.Complement(10, 90), Difference(points(IntervalSetOf(iv(10, 90))), m1)},
+		} {
+			if !Equal(points(tt.got), tt.want) {
+				t.Fatalf("%s() = %v, want %s", name, intervals(tt.got), to_string(tt.want))
+			}
+			// the result must be canonical
+			if !tt.got.Equal(IntervalSetOf(intervals(tt.got)...)) {
+				t.Fatalf("%s() is not coalesced: %v", name, intervals(tt.got))
+			}
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package sets
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/bits"
+	"net/netip"
+	"sort"
+
+	"golang.org/x/exp/slices"
+)
+
+// IPSet is a set of IP addresses stored as sorted lists of disjoint address
+// ranges, one for IPv4 and one for IPv6. Overlapping and adjacent ranges are
+// coalesced, so large prefixes such as a /8 take constant space. Contains
+// works in O(log n) of the number of ranges, and Prefixes returns the minimal
+// list of CIDR prefixes that covers the set.
+//
+// Addresses are normalized on the way in: zones are dropped and IPv4-mapped
+// IPv6 addresses are treated as IPv4. Invalid addresses, prefixes and ranges
+// are ignored.
+//
+// The zero value is an empty set ready to use.
+type IPSet struct {
+	v4, v6 ipRanges
+}
+
+// u128 is an IP address as an unsigned integer. IPv4 addresses use the low
+// 32 bits.
+type u128 struct {
+	hi, lo uint64
+}
+
+// ipRange is a closed range of addresses, first <= last.
+type ipRange struct {
+	first, last u128
+}
+
+// ipRanges is a sorted list of disjoint, non-adjacent ranges.
+type ipRanges []ipRange
+
+func u128From(a netip.Addr) u128 {
+	if a.Is4() {
+		b := a.As4()
+		return u128{0, uint64(binary.BigEndian.Uint32(b[:]))}
+	}
+	b := a.As16()
+	return u128{binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])}
+}
+
+func (u u128) addr(is4 bool) netip.Addr {
+	if is4 {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(u.lo))
+		return netip.AddrFrom4(b)
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], u.hi)
+	binary.BigEndian.PutUint64(b[8:], u.lo)
+	return netip.AddrFrom16(b)
+}
+
+func (u u128) less(v u128) bool {
+	return u.hi < v.hi || u.hi == v.hi && u.lo < v.lo
+}
+
+func (u u128) addOne() u128 {
+	lo, carry := bits.Add64(u.lo, 1, 0)
+	return u128{u.hi + carry, lo}
+}
+
+func (u u128) subOne() u128 {
+	lo, borrow := bits.Sub64(u.lo, 1, 0)
+	return u128{u.hi - borrow, lo}
+}
+
+func (u u128) or(v u128) u128 {
+	return u128{u.hi | v.hi, u.lo | v.lo}
+}
+
+// lowBits returns a value with the n low bits set.
+func lowBits(n int) u128 {
+	switch {
+	case n >= 128:
+		return u128{^uint64(0), ^uint64(0)}
+	case n > 64:
+		return u128{1<<(n-64) - 1, ^uint64(0)}
+	default:
+		return u128{0, 1<<n - 1}
+	}
+}
+
+func (u u128) trailingZeros() int {
+	if u.lo != 0 {
+		return bits.TrailingZeros64(u.lo)
+	}
+	return 64 + bits.TrailingZeros64(u.hi)
+}
+
+func minU128(a, b u128) u128 {
+	if b.less(a) {
+		return b
+	}
+	return a
+}
+
+func maxU128(a, b u128) u128 {
+	if a.less(b) {
+		return b
+	}
+	return a
+}
+
+// adjacent checks if b immediately follows a.
+func adjacent(a, b u128) bool {
+	return a.less(b) && a.addOne() == b
+}
+
+func normalizeAddr(a netip.Addr) netip.Addr {
+	return a.Unmap().WithZone("")
+}
+
+// normalizePrefix returns the masked prefix, IPv4-mapped prefixes that only
+// cover IPv4-mapped addresses are converted to IPv4.
+func normalizePrefix(p netip.Prefix) netip.Prefix {
+	a, n := p.Addr().WithZone(""), p.Bits()
+	if a.Is4In6() && n >= 96 {
+		a, n = a.Unmap(), n-96
+	}
+	p, _ = a.Prefix(n)
+	return p
+}
+
+func prefixRange(p netip.Prefix) ipRange {
+	first := u128From(p.Addr())
+	return ipRange{first, first.or(lowBits(p.Addr().BitLen() - p.Bits()))}
+}
+
+// family returns the list of ranges for addresses like a.
+func (s *IPSet) family(a netip.Addr) *ipRanges {
+	if a.Is4() {
+		return &s.v4
+	}
+	return &s.v6
+}
+
+// Add inserts the address a into s.
+func (s *IPSet) Add(a netip.Addr) {
+	s.AddRange(a, a)
+}
+
+// AddPrefix inserts all the addresses of the prefix p into s.
+func (s *IPSet) AddPrefix(p netip.Prefix) {
+	if p = normalizePrefix(p); p.IsValid() {
+		r := prefixRange(p)
+		s.family(p.Addr()).add(r.first, r.last)
+	}
+}
+
+// AddRange inserts all the addresses from first to last inclusive into s. The
+// addresses must be of the same family.
+func (s *IPSet) AddRange(first, last netip.Addr) {
+	if first, last, ok := normalizeRange(first, last); ok {
+		s.family(first).add(u128From(first), u128From(last))
+	}
+}
+
+// Remove removes the address a from s.
+func (s *IPSet) Remove(a netip.Addr) {
+	s.RemoveRange(a, a)
+}
+
+// RemovePrefix removes all the addresses of the prefix p from s.
+func (s *IPSet) RemovePrefix(p netip.Prefix) {
+	if p = normalizePrefix(p); p.IsValid() {
+		r := prefixRange(p)
+		s.family(p.Addr()).remove(r.first, r.last)
+	}
+}
+
+// RemoveRange removes all the addresses from first to last inclusive from s.
+// The addresses must be of the same family.
+func (s *IPSet) RemoveRange(first, last netip.Addr) {
+	if first, last, ok := normalizeRange(first, last); ok {
+		s.family(first).remove(u128From(first), u128From(last))
+	}
+}
+
+func normalizeRange(first, last netip.Addr) (netip.Addr, netip.Addr, bool) {
+	first, last = normalizeAddr(first), normalizeAddr(last)
+	ok := first.IsValid() && last.IsValid() && first.Is4() == last.Is4() && first.Compare(last) <= 0
+	return first, last, ok
+}
+
+// Contains checks if the address a is in s.
+func (s *IPSet) Contains(a netip.Addr) bool {
+	if a = normalizeAddr(a); !a.IsValid() {
+		return false
+	}
+	x := u128From(a)
+	return s.family(a).contains(x, x)
+}
+
+// ContainsPrefix checks if all the addresses of the prefix p are in s.
+func (s *IPSet) ContainsPrefix(p netip.Prefix) bool {
+	if p = normalizePrefix(p); !p.IsValid() {
+		return false
+	}
+	r := prefixRange(p)
+	return s.family(p.Addr()).contains(r.first, r.last)
+}
+
+// Empty checks if there are no addresses in s.
+func (s *IPSet) Empty() bool {
+	return len(s.v4) == 0 && len(s.v6) == 0
+}
+
+// Clear removes all addresses from s.
+func (s *IPSet) Clear() {
+	s.v4, s.v6 = nil, nil
+}
+
+// Clone returns a copy of s.
+func (s *IPSet) Clone() *IPSet {
+	return &IPSet{slices.Clone(s.v4), slices.Clone(s.v6)}
+}
+
+// Equal checks if s and o contain the same addresses.
+func (s *IPSet) Equal(o *IPSet) bool {
+	return slices.Equal(s.v4, o.v4) && slices.Equal(s.v6, o.v6)
+}
+
+// Union returns a new set with the addresses from both s and o.
+func (s *IPSet) Union(o *IPSet) *IPSet {
+	return &IPSet{s.v4.union(o.v4), s.v6.union(o.v6)}
+}
+
+// Intersection returns a new set with the addresses that are both in s and o.
+func (s *IPSet) Intersection(o *IPSet) *IPSet {
+	return &IPSet{s.v4.intersection(o.v4), s.v6.intersection(o.v6)}
+}
+
+// Difference returns a new set with the addresses from s that are not in o.
+func (s *IPSet) Difference(o *IPSet) *IPSet {
+	return &IPSet{s.v4.difference(o.v4), s.v6.difference(o.v6)}
+}
+
+// Ranges returns an iterator over the disjoint address ranges of s as pairs
+// of the first and the last address. IPv4 ranges come first, each family is
+// sorted in ascending order.
+func (s *IPSet) Ranges() iter.Seq2[netip.Addr, netip.Addr] {
+	return func(yield func(netip.Addr, netip.Addr) bool) {
+		for _, r := range s.v4 {
+			if !yield(r.first.addr(true), r.last.addr(true)) {
+				return
+			}
+		}
+		for _, r := range s.v6 {
+			if !yield(r.first.addr(false), r.last.addr(false)) {
+				return
+			}
+		}
+	}
+}
+
+// Prefixes returns the minimal list of CIDR prefixes that covers exactly the
+// addresses in s. IPv4 prefixes come first, each family is sorted in
+// ascending order.
+func (s *IPSet) Prefixes() []netip.Prefix {
+	var r []netip.Prefix
+	r = s.v4.prefixes(r, true)
+	return s.v6.prefixes(r, false)
+}
+
+// IPSetFrom returns an IP set with the addresses in s.
+func IPSetFrom[S ~map[netip.Addr]struct{}](s S) *IPSet {
+	r := &IPSet{}
+	for a := range s {
+		r.Add(a)
+	}
+	return r
+}
+
+func (rs ipRanges) prefixes(r []netip.Prefix, is4 bool) []netip.Prefix {
+	bitlen := 128
+	if is4 {
+		bitlen = 32
+	}
+	for _, rg := range rs {
+		first := rg.first
+		for {
+			// the largest aligned block that starts at first and ends
+			// within the range
+			n := min(first.trailingZeros(), bitlen)
+			for n > 0 && rg.last.less(first.or(lowBits(n))) {
+				n--
+			}
+			r = append(r, netip.PrefixFrom(first.addr(is4), bitlen-n))
+			last := first.or(lowBits(n))
+			if last == rg.last {
+				break
+			}
+			first = last.addOne()
+		}
+	}
+	return r
+}
+
+// add inserts the range [first, last] coalescing it with the overlapping and
+// adjacent ranges.
+func (rs *ipRanges) add(first, last u128) {
+	s := *rs
+	i := sort.Search(len(s), func(i int) bool { return !s[i].last.less(first) || adjacent(s[i].last, first) })
+	j := sort.Search(len(s), func(i int) bool { return last.less(s[i].first) && !adjacent(last, s[i].first) })
+	if i < j {
+		first = minU128(first, s[i].first)
+		last = maxU128(last, s[j-1].last)
+	}
+	*rs = slices.Insert(slices.Delete(s, i, j), i, ipRange{first, last})
+}
+
+// remove removes the range [first, last].
+func (rs *ipRanges) remove(first, last u128) {
+	s := *rs
+	i := sort.Search(len(s), func(i int) bool { return !s[i].last.less(first) })
+	j := sort.Search(len(s), func(i int) bool { return last.less(s[i].first) })
+	if i >= j {
+		return
+	}
+	var rest []ipRange
+	if s[i].first.less(first) {
+		rest = append(rest, ipRange{s[i].first, first.subOne()})
+	}
+	if last.less(s[j-1].last) {
+		rest = append(rest, ipRange{last.addOne(), s[j-1].last})
+	}
+	*rs = slices.Insert(slices.Delete(s, i, j), i, rest...)
+}
+
+// contains checks if the whole range [first, last] is in rs.
+func (rs ipRanges) contains(first, last u128) bool {
+	i := sort.Search(len(rs), func(i int) bool { return !rs[i].last.less(first) })
+	return i < len(rs) && !first.less(rs[i].first) && !rs[i].last.less(last)
+}
+
+func (rs ipRanges) union(o ipRanges) ipRanges {
+	r := make(ipRanges, 0, len(rs)+len(o))
+	add := func(rg ipRange) {
+		if n := len(r); n > 0 && (!r[n-1].last.less(rg.first) || adjacent(r[n-1].last, rg.first)) {
+			r[n-1].last = maxU128(r[n-1].last, rg.last)
+		} else {
+			r = append(r, rg)
+		}
+	}
+	i, j := 0, 0
+	for i < len(rs) && j < len(o) {
+		if !o[j].first.less(rs[i].first) {
+			add(rs[i])
+			i++
+		} else {
+			add(o[j])
+			j++
+		}
+	}
+	for ; i < len(rs); i++ {
+		add(rs[i])
+	}
+	for ; j < len(o); j++ {
+		add(o[j])
+	}
+	return r
+}
+
+func (rs ipRanges) intersection(o ipRanges) ipRanges {
+	var r ipRanges
+	for i, j := 0, 0; i < len(rs) && j < len(o); {
+		first, last := maxU128(rs[i].first, o[j].first), minU128(rs[i].last, o[j].last)
+		if !last.less(first) {
+			r = append(r, ipRange{first, last})
+		}
+		if rs[i].last.less(o[j].last) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return r
+}
+
+func (rs ipRanges) difference(o ipRanges) ipRanges {
+	var r ipRanges
+	j := 0
+	for _, rg := range rs {
+		for j < len(o) && o[j].last.less(rg.first) {
+			j++
+		}
+		first, done := rg.first, false
+		for k := j; k < len(o) && !rg.last.less(o[k].first); k++ {
+			if first.less(o[k].first) {
+				r = append(r, ipRange{first, o[k].first.subOne()})
+			}
+			if !o[k].last.less(rg.last) {
+				done = true
+				break
+			}
+			first = maxU128(first, o[k].last.addOne())
+		}
+		if !done {
+			r = append(r, ipRange{first, rg.last})
+		}
+	}
+	return r
+}
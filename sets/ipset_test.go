@@ -0,0 +1,194 @@
+package sets
+
+import (
+	"math/rand"
+	"net/netip"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func prefixes(ss ...string) []netip.Prefix {
+	r := make([]netip.Prefix, len(ss))
+	for i, s := range ss {
+		r[i] = netip.MustParsePrefix(s)
+	}
+	return r
+}
+
+func TestIPSetPrefixes(t *testing.T) {
+	addr := netip.MustParseAddr
+	tests := []struct {
+		name  string
+		build func(s *IPSet)
+		want  []netip.Prefix
+	}{
+		{"coalesce halves", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("10.0.0.128/25"))
+			s.AddPrefix(netip.MustParsePrefix("10.0.0.0/25"))
+		}, prefixes("10.0.0.0/24")},
+		{"overlapping", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+			s.AddPrefix(netip.MustParsePrefix("10.1.2.0/24"))
+		}, prefixes("10.0.0.0/8")},
+		{"unmasked prefix", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("192.168.1.77/24"))
+		}, prefixes("192.168.1.0/24")},
+		{"range", func(s *IPSet) {
+			s.AddRange(addr("10.0.0.1"), addr("10.0.0.6"))
+		}, prefixes("10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32")},
+		{"adjacent addresses", func(s *IPSet) {
+			s.Add(addr("10.0.0.3"))
+			s.Add(addr("10.0.0.2"))
+		}, prefixes("10.0.0.2/31")},
+		{"hole", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("10.0.0.0/30"))
+			s.Remove(addr("10.0.0.1"))
+		}, prefixes("10.0.0.0/32", "10.0.0.2/31")},
+		{"mixed families", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("2001:db8::/32"))
+			s.AddPrefix(netip.MustParsePrefix("192.0.2.0/24"))
+		}, prefixes("192.0.2.0/24", "2001:db8::/32")},
+		{"mapped and zoned", func(s *IPSet) {
+			s.Add(addr("::ffff:192.0.2.1"))
+			s.AddPrefix(netip.MustParsePrefix("::ffff:192.0.2.2/127"))
+			s.Add(addr("fe80::1%eth0"))
+		}, prefixes("192.0.2.1/32", "192.0.2.2/31", "fe80::1/128")},
+		{"everything", func(s *IPSet) {
+			s.AddPrefix(netip.MustParsePrefix("0.0.0.0/1"))
+			s.AddPrefix(netip.MustParsePrefix("128.0.0.0/1"))
+			s.AddRange(addr("::"), addr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"))
+		}, prefixes("0.0.0.0/0", "::/0")},
+		{"invalid input", func(s *IPSet) {
+			s.Add(netip.Addr{})
+			s.AddPrefix(netip.Prefix{})
+			s.AddRange(addr("10.0.0.2"), addr("10.0.0.1"))
+			s.AddRange(addr("10.0.0.1"), addr("::1"))
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s IPSet
+			tt.build(&s)
+			if got := s.Prefixes(); !slices.Equal(got, tt.want) {
+				t.Errorf("Prefixes() = %v, want %v", got, tt.want)
+			}
+			rebuilt := &IPSet{}
+			for _, p := range s.Prefixes() {
+				rebuilt.AddPrefix(p)
+			}
+			if !rebuilt.Equal(&s) {
+				t.Errorf("Prefixes() do not cover the set")
+			}
+		})
+	}
+}
+
+func TestIPSetEdges(t *testing.T) {
+	var s IPSet
+	s.AddPrefix(netip.MustParsePrefix("::/0"))
+	s.Remove(netip.MustParseAddr("::"))
+	s.Remove(netip.MustParseAddr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"))
+	got := s.Prefixes()
+	if len(got) != 254 || got[0] != netip.MustParsePrefix("::1/128") || got[253] != netip.MustParsePrefix("ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/128") {
+		t.Errorf("Prefixes() = %d prefixes from %v to %v", len(got), got[0], got[len(got)-1])
+	}
+	s.Add(netip.MustParseAddr("::"))
+	s.Add(netip.MustParseAddr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"))
+	if got := s.Prefixes(); !slices.Equal(got, prefixes("::/0")) {
+		t.Errorf("Prefixes() = %v", got)
+	}
+}
+
+func TestIPSetContains(t *testing.T) {
+	var s IPSet
+	s.AddPrefix(netip.MustParsePrefix("10.0.0.0/8"))
+	s.AddPrefix(netip.MustParsePrefix("2001:db8::/32"))
+	for a, want := range map[string]bool{
+		"10.0.0.0":         true,
+		"10.255.255.255":   true,
+		"11.0.0.0":         false,
+		"9.255.255.255":    false,
+		"::ffff:10.1.2.3":  true,
+		"2001:db8::1%eth0": true,
+		"2001:db9::":       false,
+		"::a00:1":          false,
+	} {
+		if got := s.Contains(netip.MustParseAddr(a)); got != want {
+			t.Errorf("Contains(%s) = %v", a, got)
+		}
+	}
+	for p, want := range map[string]bool{
+		"10.1.0.0/16":             true,
+		"10.0.0.0/7":              false,
+		"::ffff:10.0.0.0/104":     true,
+		"2001:db8:1::/48":         true,
+		"2001:db8:ffff:ffff::/31": false,
+	} {
+		if got := s.ContainsPrefix(netip.MustParsePrefix(p)); got != want {
+			t.Errorf("ContainsPrefix(%s) = %v", p, got)
+		}
+	}
+	if s.Contains(netip.Addr{}) {
+		t.Errorf("Contains() of an invalid address")
+	}
+}
+
+func TestIPSetAlgebra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	base := netip.MustParseAddr("192.168.0.0")
+	at := func(i int) netip.Addr {
+		a := base
+		for ; i > 0; i-- {
+			a = a.Next()
+		}
+		return a
+	}
+	random := func() (*IPSet, map[netip.Addr]struct{}) {
+		s, m := &IPSet{}, map[netip.Addr]struct{}{}
+		for i := rnd.Intn(6); i > 0; i-- {
+			lo := rnd.Intn(256)
+			hi := lo + rnd.Intn(40)
+			if rnd.Intn(3) == 0 {
+				s.RemoveRange(at(lo), at(hi))
+				for x := lo; x <= hi; x++ {
+					delete(m, at(x))
+				}
+			} else {
+				s.AddRange(at(lo), at(hi))
+				for x := lo; x <= hi; x++ {
+					m[at(x)] = struct{}{}
+				}
+			}
+		}
+		return s, m
+	}
+	addrs := func(s *IPSet) map[netip.Addr]struct{} {
+		r := map[netip.Addr]struct{}{}
+		for first, last := range s.Ranges() {
+			for a := first; a.Compare(last) <= 0; a = a.Next() {
+				r[a] = struct{}{}
+			}
+		}
+		return r
+	}
+	for i := 0; i < 200; i++ {
+		s1, m1 := random()
+		s2, m2 := random()
+		if !Equal(addrs(s1), m1) || !IPSetFrom(m1).Equal(s1) {
+			t.Fatalf("%v does not match the reference", s1.Prefixes())
+		}
+		for name, tt := range map[string]struct {
+			got  *IPSet
+			want map[netip.Addr]struct{}
+		}{
+			"Union":        {s1.Union(s2), Union(m1, m2)},
+			"Intersection": {s1.Intersection(s2), Intersection(m1, m2)},
+			"Difference":   {s1.Difference(s2), Difference(m1, m2)},
+		} {
+			if !Equal(addrs(tt.got), tt.want) || !IPSetFrom(tt.want).Equal(tt.got) {
+				t.Fatalf("%s() = %v", name, tt.got.Prefixes())
+			}
+		}
+	}
+}
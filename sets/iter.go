@@ -0,0 +1,64 @@
+package sets
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// All returns an iterator over the keys in s. The keys will be in an
+// indeterminate order. Unlike Keys, no intermediate slice is allocated.
+func All[S ~map[K]struct{}, K comparable](s S) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// AllSorted returns an iterator over the keys in s in sorted order. It is the
+// iterator counterpart of Sorted.
+func AllSorted[S ~map[K]struct{}, K constraints.Ordered](s S) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		r := Keys(s)
+		slices.Sort(r)
+		yieldKeys(r, yield)
+	}
+}
+
+// AllSortedFunc returns an iterator over the keys in s sorted as determined by
+// the less function. It is the iterator counterpart of SortedFunc.
+func AllSortedFunc[S ~map[K]struct{}, K comparable](s S, less func(a, b K) bool) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		r := Keys(s)
+		slices.SortFunc(r, less)
+		yieldKeys(r, yield)
+	}
+}
+
+// Collect collects the keys produced by seq into a new set.
+func Collect[K comparable](seq iter.Seq[K]) map[K]struct{} {
+	r := map[K]struct{}{}
+	for k := range seq {
+		r[k] = struct{}{}
+	}
+	return r
+}
+
+// InsertSeq inserts the keys produced by seq into s.
+func InsertSeq[S ~map[K]struct{}, K comparable](s S, seq iter.Seq[K]) {
+	for k := range seq {
+		s[k] = struct{}{}
+	}
+}
+
+func yieldKeys[K any](keys []K, yield func(K) bool) {
+	for _, k := range keys {
+		if !yield(k) {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package sets
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestAllSorted(t *testing.T) {
+	tests := []struct {
+		s    map[int]struct{}
+		want []int
+	}{
+		{empty, nil},
+		{set(1), []int{1}},
+		{set(3, 1, 2), []int{1, 2, 3}},
+		{set(5, -1, 3, 0), []int{-1, 0, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(to_string(tt.s), func(t *testing.T) {
+			var got []int
+			for k := range AllSorted(tt.s) {
+				got = append(got, k)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AllSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllSortedBreak(t *testing.T) {
+	var got []int
+	for k := range AllSorted(set(4, 3, 2, 1)) {
+		if k > 2 {
+			break
+		}
+		got = append(got, k)
+	}
+	if want := []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("AllSorted() = %v, want %v", got, want)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		s map[int]struct{}
+	}{
+		{empty},
+		{set(1)},
+		{set(1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(to_string(tt.s), func(t *testing.T) {
+			if got := Collect(All(tt.s)); !Equal(tt.s, got) {
+				t.Errorf("Collect() = %s, want %s", to_string(got), to_string(tt.s))
+			}
+		})
+	}
+}
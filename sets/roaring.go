@@ -0,0 +1,551 @@
+package sets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+	"math/bits"
+
+	"golang.org/x/exp/slices"
+)
+
+// ErrRoaringFormat is returned when decoding data that is not a valid
+// serialized roaring bitmap.
+var ErrRoaringFormat = errors.New("sets: invalid roaring bitmap data")
+
+// Roaring is a compressed set of uint32 values, implemented as a roaring
+// bitmap. The values are partitioned by their high 16 bits into containers,
+// each stored as a sorted array, a bitset or a list of runs, whichever is
+// appropriate for its density. Large sets of IDs typically take a few bits
+// per value, and the set algebra works on whole containers at a time.
+//
+// Insert and Remove keep the containers as arrays or bitsets; call
+// RunOptimize to compress sequences of consecutive values into runs.
+//
+// Roaring sets can be serialized in the portable format that is shared by
+// the roaring bitmap implementations in other languages.
+//
+// The zero value is an empty set ready to use.
+type Roaring struct {
+	keys []uint16 // sorted high bits
+	cs   []container
+}
+
+// NewRoaring returns a roaring set containing the values.
+func NewRoaring(vals ...uint32) *Roaring {
+	r := &Roaring{}
+	r.Insert(vals...)
+	return r
+}
+
+// RoaringFrom returns a roaring set with the keys of s.
+func RoaringFrom[S ~map[uint32]struct{}](s S) *Roaring {
+	vals := make([]uint32, 0, len(s))
+	for k := range s {
+		vals = append(vals, k)
+	}
+	slices.Sort(vals)
+	return NewRoaring(vals...)
+}
+
+func splitRoaring(v uint32) (hi, lo uint16) {
+	return uint16(v >> 16), uint16(v)
+}
+
+// Count returns the number of values in r.
+func (r *Roaring) Count() int {
+	n := 0
+	for _, c := range r.cs {
+		n += c.card()
+	}
+	return n
+}
+
+// Contains checks if there is a value in r.
+func (r *Roaring) Contains(v uint32) bool {
+	hi, lo := splitRoaring(v)
+	i, ok := slices.BinarySearch(r.keys, hi)
+	return ok && r.cs[i].contains(lo)
+}
+
+// Insert inserts the values into r.
+func (r *Roaring) Insert(vals ...uint32) {
+	for _, v := range vals {
+		hi, lo := splitRoaring(v)
+		i, ok := slices.BinarySearch(r.keys, hi)
+		if ok {
+			r.cs[i] = r.cs[i].add(lo)
+			continue
+		}
+		r.keys = slices.Insert(r.keys, i, hi)
+		r.cs = slices.Insert(r.cs, i, container(arrayContainer{lo}))
+	}
+}
+
+// Remove removes the values from r.
+func (r *Roaring) Remove(vals ...uint32) {
+	for _, v := range vals {
+		hi, lo := splitRoaring(v)
+		i, ok := slices.BinarySearch(r.keys, hi)
+		if !ok {
+			continue
+		}
+		if r.cs[i] = r.cs[i].remove(lo); r.cs[i].card() == 0 {
+			r.keys = slices.Delete(r.keys, i, i+1)
+			r.cs = slices.Delete(r.cs, i, i+1)
+		}
+	}
+}
+
+// Clear removes all values from r.
+func (r *Roaring) Clear() {
+	r.keys, r.cs = nil, nil
+}
+
+// Clone returns a copy of r.
+func (r *Roaring) Clone() *Roaring {
+	c := &Roaring{keys: slices.Clone(r.keys), cs: make([]container, len(r.cs))}
+	for i := range r.cs {
+		c.cs[i] = r.cs[i].clone()
+	}
+	return c
+}
+
+// RunOptimize converts each container into its most compact representation,
+// which compresses sequences of consecutive values into runs.
+func (r *Roaring) RunOptimize() {
+	for i := range r.cs {
+		r.cs[i] = optimize(r.cs[i])
+	}
+}
+
+// Min returns the smallest value in r.
+func (r *Roaring) Min() (v uint32, ok bool) {
+	if len(r.cs) == 0 {
+		return
+	}
+	r.cs[0].all(func(lo uint16) bool {
+		v = uint32(r.keys[0])<<16 | uint32(lo)
+		return false
+	})
+	return v, true
+}
+
+// Max returns the largest value in r.
+func (r *Roaring) Max() (v uint32, ok bool) {
+	n := len(r.cs)
+	if n == 0 {
+		return
+	}
+	var lo uint16
+	switch c := r.cs[n-1].(type) {
+	case arrayContainer:
+		lo = c[len(c)-1]
+	case runContainer:
+		lo = c[len(c)-1].last
+	case *bitmapContainer:
+		i := len(c.w) - 1
+		for c.w[i] == 0 {
+			i--
+		}
+		lo = uint16(i*64 + 63 - bits.LeadingZeros64(c.w[i]))
+	}
+	return uint32(r.keys[n-1])<<16 | uint32(lo), true
+}
+
+// All returns an iterator over the values of r in ascending order.
+func (r *Roaring) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range r.cs {
+			hi := uint32(r.keys[i]) << 16
+			if !c.all(func(lo uint16) bool { return yield(hi | uint32(lo)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the values of r as a slice in ascending order.
+func (r *Roaring) Keys() []uint32 {
+	vals := make([]uint32, 0, r.Count())
+	for v := range r.All() {
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// Set returns the values of r as a map-based set.
+func (r *Roaring) Set() Set[uint32] {
+	s := make(Set[uint32], r.Count())
+	for v := range r.All() {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+// Equal checks if r and o contain the same values.
+func (r *Roaring) Equal(o *Roaring) bool {
+	if !slices.Equal(r.keys, o.keys) {
+		return false
+	}
+	for i := range r.cs {
+		n := r.cs[i].card()
+		if n != o.cs[i].card() || andCardinality(r.cs[i], o.cs[i]) != n {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset checks if all the values of r are in o.
+func (r *Roaring) IsSubset(o *Roaring) bool {
+	for i, hi := range r.keys {
+		j, ok := slices.BinarySearch(o.keys, hi)
+		if !ok || andCardinality(r.cs[i], o.cs[j]) != r.cs[i].card() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint checks if r and o have no values in common.
+func (r *Roaring) IsDisjoint(o *Roaring) bool {
+	disjoint := true
+	r.matching(o, func(a, b container) bool {
+		disjoint = andCardinality(a, b) == 0
+		return disjoint
+	})
+	return disjoint
+}
+
+// IntersectionCount returns the number of values that are both in r and o,
+// without building the intersection.
+func (r *Roaring) IntersectionCount(o *Roaring) int {
+	n := 0
+	r.matching(o, func(a, b container) bool {
+		n += andCardinality(a, b)
+		return true
+	})
+	return n
+}
+
+// matching calls fn for the pairs of containers of r and o with the same key
+// while fn returns true.
+func (r *Roaring) matching(o *Roaring, fn func(a, b container) bool) {
+	for i, j := 0, 0; i < len(r.keys) && j < len(o.keys); {
+		switch {
+		case r.keys[i] < o.keys[j]:
+			i++
+		case r.keys[i] > o.keys[j]:
+			j++
+		default:
+			if !fn(r.cs[i], o.cs[j]) {
+				return
+			}
+			i++
+			j++
+		}
+	}
+}
+
+// combine builds a new set from r and o. Containers that are only in r or
+// only in o are copied if keep_r or keep_o is set; containers with the same
+// key are combined with op.
+func (r *Roaring) combine(o *Roaring, keep_r, keep_o bool, op func(a, b container) container) *Roaring {
+	res := &Roaring{}
+	add := func(hi uint16, c container) {
+		if c.card() > 0 {
+			res.keys = append(res.keys, hi)
+			res.cs = append(res.cs, c)
+		}
+	}
+	i, j := 0, 0
+	for i < len(r.keys) && j < len(o.keys) {
+		switch {
+		case r.keys[i] < o.keys[j]:
+			if keep_r {
+				add(r.keys[i], r.cs[i].clone())
+			}
+			i++
+		case r.keys[i] > o.keys[j]:
+			if keep_o {
+				add(o.keys[j], o.cs[j].clone())
+			}
+			j++
+		default:
+			add(r.keys[i], op(r.cs[i], o.cs[j]))
+			i++
+			j++
+		}
+	}
+	for ; keep_r && i < len(r.keys); i++ {
+		add(r.keys[i], r.cs[i].clone())
+	}
+	for ; keep_o && j < len(o.keys); j++ {
+		add(o.keys[j], o.cs[j].clone())
+	}
+	return res
+}
+
+// Union returns a new set with the values from both r and o.
+func (r *Roaring) Union(o *Roaring) *Roaring {
+	return r.combine(o, true, true, orContainers)
+}
+
+// Intersection returns a new set with the values that are both in r and o.
+func (r *Roaring) Intersection(o *Roaring) *Roaring {
+	return r.combine(o, false, false, andContainers)
+}
+
+// Difference returns a new set with the values from r that are not in o.
+func (r *Roaring) Difference(o *Roaring) *Roaring {
+	return r.combine(o, true, false, andNotContainers)
+}
+
+// SymmetricDifference returns a new set with the values that are either in r
+// or in o, but not in both.
+func (r *Roaring) SymmetricDifference(o *Roaring) *Roaring {
+	return r.combine(o, true, true, xorContainers)
+}
+
+// Merge inserts all the values of o into r.
+func (r *Roaring) Merge(o *Roaring) {
+	*r = *r.Union(o)
+}
+
+// Intersect removes the values of r that are not in o.
+func (r *Roaring) Intersect(o *Roaring) {
+	*r = *r.Intersection(o)
+}
+
+// Subtract removes the values of o from r.
+func (r *Roaring) Subtract(o *Roaring) {
+	*r = *r.Difference(o)
+}
+
+// SymmetricSubtract removes the values of o that are in r from r, and inserts
+// the rest of the values of o into r.
+func (r *Roaring) SymmetricSubtract(o *Roaring) {
+	*r = *r.SymmetricDifference(o)
+}
+
+// cookies and thresholds of the portable serialization format
+const (
+	roaringCookieNoRuns    = 12346
+	roaringCookie          = 12347
+	roaringNoOffsetMaxSize = 4
+)
+
+// WriteTo writes r to w in the portable roaring bitmap serialization format.
+// It implements io.WriterTo.
+func (r *Roaring) WriteTo(w io.Writer) (int64, error) {
+	size := len(r.cs)
+	has_runs := false
+	for _, c := range r.cs {
+		if _, ok := c.(runContainer); ok {
+			has_runs = true
+			break
+		}
+	}
+
+	var buf []byte
+	le := binary.LittleEndian
+	with_offsets := true
+	if has_runs {
+		buf = le.AppendUint32(buf, roaringCookie|uint32(size-1)<<16)
+		run_bitmap := make([]byte, (size+7)/8)
+		for i, c := range r.cs {
+			if _, ok := c.(runContainer); ok {
+				run_bitmap[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf = append(buf, run_bitmap...)
+		with_offsets = size >= roaringNoOffsetMaxSize
+	} else {
+		buf = le.AppendUint32(buf, roaringCookieNoRuns)
+		buf = le.AppendUint32(buf, uint32(size))
+	}
+	for i, c := range r.cs {
+		buf = le.AppendUint16(buf, r.keys[i])
+		buf = le.AppendUint16(buf, uint16(c.card()-1))
+	}
+	if with_offsets {
+		offset := len(buf) + 4*size
+		for _, c := range r.cs {
+			buf = le.AppendUint32(buf, uint32(offset))
+			offset += serializedSize(c)
+		}
+	}
+	for _, c := range r.cs {
+		switch c := c.(type) {
+		case runContainer:
+			buf = le.AppendUint16(buf, uint16(len(c)))
+			for _, rn := range c {
+				buf = le.AppendUint16(buf, rn.start)
+				buf = le.AppendUint16(buf, rn.last-rn.start)
+			}
+		default:
+			if c.card() > arrayMaxSize {
+				for _, x := range toBitmap(c).w {
+					buf = le.AppendUint64(buf, x)
+				}
+			} else {
+				c.all(func(x uint16) bool {
+					buf = le.AppendUint16(buf, x)
+					return true
+				})
+			}
+		}
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+func serializedSize(c container) int {
+	switch c := c.(type) {
+	case runContainer:
+		return 2 + 4*len(c)
+	default:
+		if c.card() > arrayMaxSize {
+			return 8 * bitmapWords
+		}
+		return 2 * c.card()
+	}
+}
+
+// ReadFrom replaces the contents of r with a set read from rd in the portable
+// roaring bitmap serialization format. It reads exactly the serialized bytes
+// and implements io.ReaderFrom.
+func (r *Roaring) ReadFrom(rd io.Reader) (int64, error) {
+	var n int64
+	read := func(size int) ([]byte, error) {
+		buf := make([]byte, size)
+		m, err := io.ReadFull(rd, buf)
+		n += int64(m)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf, err
+	}
+	le := binary.LittleEndian
+
+	hdr, err := read(4)
+	if err != nil {
+		return n, err
+	}
+	var size int
+	var run_bitmap []byte
+	with_offsets := true
+	switch cookie := le.Uint32(hdr); {
+	case cookie == roaringCookieNoRuns:
+		if hdr, err = read(4); err != nil {
+			return n, err
+		}
+		size = int(le.Uint32(hdr))
+		if size > 1<<16 {
+			return n, ErrRoaringFormat
+		}
+	case cookie&0xffff == roaringCookie:
+		size = int(cookie>>16) + 1
+		if run_bitmap, err = read((size + 7) / 8); err != nil {
+			return n, err
+		}
+		with_offsets = size >= roaringNoOffsetMaxSize
+	default:
+		return n, ErrRoaringFormat
+	}
+
+	desc, err := read(4 * size)
+	if err != nil {
+		return n, err
+	}
+	if with_offsets {
+		if _, err = read(4 * size); err != nil {
+			return n, err
+		}
+	}
+
+	res := Roaring{keys: make([]uint16, size), cs: make([]container, size)}
+	for i := 0; i < size; i++ {
+		res.keys[i] = le.Uint16(desc[4*i:])
+		if i > 0 && res.keys[i] <= res.keys[i-1] {
+			return n, ErrRoaringFormat
+		}
+		card := int(le.Uint16(desc[4*i+2:])) + 1
+		switch {
+		case run_bitmap != nil && run_bitmap[i/8]&(1<<(i%8)) != 0:
+			b, err := read(2)
+			if err != nil {
+				return n, err
+			}
+			runs := int(le.Uint16(b))
+			if b, err = read(4 * runs); err != nil {
+				return n, err
+			}
+			c := make(runContainer, runs)
+			for j := range c {
+				start, length := le.Uint16(b[4*j:]), le.Uint16(b[4*j+2:])
+				if int(start)+int(length) > 0xffff || j > 0 && int(start) <= int(c[j-1].last) {
+					return n, ErrRoaringFormat
+				}
+				c[j] = run16{start, start + length}
+			}
+			if c.card() != card {
+				return n, ErrRoaringFormat
+			}
+			res.cs[i] = c
+		case card > arrayMaxSize:
+			b, err := read(8 * bitmapWords)
+			if err != nil {
+				return n, err
+			}
+			c := &bitmapContainer{}
+			for j := range c.w {
+				c.w[j] = le.Uint64(b[8*j:])
+			}
+			if c.normalize(); c.n != card {
+				return n, ErrRoaringFormat
+			}
+			res.cs[i] = c
+		default:
+			b, err := read(2 * card)
+			if err != nil {
+				return n, err
+			}
+			c := make(arrayContainer, card)
+			for j := range c {
+				c[j] = le.Uint16(b[2*j:])
+				if j > 0 && c[j] <= c[j-1] {
+					return n, ErrRoaringFormat
+				}
+			}
+			res.cs[i] = c
+		}
+	}
+	*r = res
+	return n, nil
+}
+
+// MarshalBinary returns r in the portable roaring bitmap serialization format.
+// It implements encoding.BinaryMarshaler.
+func (r *Roaring) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary replaces the contents of r with a set decoded from data in
+// the portable roaring bitmap serialization format. It implements
+// encoding.BinaryUnmarshaler.
+func (r *Roaring) UnmarshalBinary(data []byte) error {
+	rd := bytes.NewReader(data)
+	if _, err := r.ReadFrom(rd); err != nil {
+		return err
+	}
+	if rd.Len() != 0 {
+		return ErrRoaringFormat
+	}
+	return nil
+}
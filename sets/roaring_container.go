@@ -0,0 +1,584 @@
+package sets
+
+import (
+	"math/bits"
+	"sort"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	arrayMaxSize = 4096 // larger containers are stored as bitmaps
+	bitmapWords  = 1 << 16 / 64
+)
+
+// container holds the low 16 bits of the values of a Roaring set that share
+// the same high 16 bits. Containers never share storage, mutating methods may
+// modify the receiver and return it or return a container of another type.
+type container interface {
+	card() int
+	contains(x uint16) bool
+	add(x uint16) container
+	remove(x uint16) container
+	all(yield func(uint16) bool) bool
+	clone() container
+}
+
+// arrayContainer is a sorted list of values, used for sparse containers.
+type arrayContainer []uint16
+
+// bitmapContainer is a bitset, used for dense containers.
+type bitmapContainer struct {
+	w [bitmapWords]uint64
+	n int
+}
+
+// runContainer is a sorted list of non-overlapping, non-adjacent intervals,
+// used for containers with long sequences of consecutive values.
+type runContainer []run16
+
+// run16 is a closed interval of values.
+type run16 struct {
+	start, last uint16
+}
+
+func (a arrayContainer) card() int { return len(a) }
+
+func (a arrayContainer) contains(x uint16) bool {
+	_, ok := slices.BinarySearch(a, x)
+	return ok
+}
+
+func (a arrayContainer) add(x uint16) container {
+	i, ok := slices.BinarySearch(a, x)
+	if ok {
+		return a
+	}
+	if len(a) == arrayMaxSize {
+		return a.toBitmap().add(x)
+	}
+	return slices.Insert(a, i, x)
+}
+
+func (a arrayContainer) remove(x uint16) container {
+	if i, ok := slices.BinarySearch(a, x); ok {
+		return slices.Delete(a, i, i+1)
+	}
+	return a
+}
+
+func (a arrayContainer) all(yield func(uint16) bool) bool {
+	for _, x := range a {
+		if !yield(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a arrayContainer) clone() container {
+	return append(arrayContainer(nil), a...)
+}
+
+func (a arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{n: len(a)}
+	for _, x := range a {
+		b.w[x/64] |= 1 << (x % 64)
+	}
+	return b
+}
+
+func (b *bitmapContainer) card() int { return b.n }
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.w[x/64]&(1<<(x%64)) != 0
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	if !b.contains(x) {
+		b.w[x/64] |= 1 << (x % 64)
+		b.n++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	if !b.contains(x) {
+		return b
+	}
+	b.w[x/64] &^= 1 << (x % 64)
+	b.n--
+	if b.n <= arrayMaxSize {
+		return b.toArray()
+	}
+	return b
+}
+
+func (b *bitmapContainer) all(yield func(uint16) bool) bool {
+	for i, w := range b.w {
+		for ; w != 0; w &= w - 1 {
+			if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) clone() container {
+	r := *b
+	return &r
+}
+
+func (b *bitmapContainer) toArray() arrayContainer {
+	r := make(arrayContainer, 0, b.n)
+	b.all(func(x uint16) bool {
+		r = append(r, x)
+		return true
+	})
+	return r
+}
+
+// normalize recounts the values after a bulk update and converts b into an
+// array if it became sparse.
+func (b *bitmapContainer) normalize() container {
+	b.n = 0
+	for _, w := range b.w {
+		b.n += bits.OnesCount64(w)
+	}
+	if b.n <= arrayMaxSize {
+		return b.toArray()
+	}
+	return b
+}
+
+// setRange sets the bits from start to last inclusive.
+func (b *bitmapContainer) setRange(start, last uint16) {
+	first_word, last_word := start/64, last/64
+	first_mask := ^uint64(0) << (start % 64)
+	last_mask := ^uint64(0) >> (63 - last%64)
+	if first_word == last_word {
+		b.w[first_word] |= first_mask & last_mask
+		return
+	}
+	b.w[first_word] |= first_mask
+	for i := first_word + 1; i < last_word; i++ {
+		b.w[i] = ^uint64(0)
+	}
+	b.w[last_word] |= last_mask
+}
+
+func (r runContainer) card() int {
+	n := 0
+	for _, rn := range r {
+		n += int(rn.last-rn.start) + 1
+	}
+	return n
+}
+
+func (r runContainer) contains(x uint16) bool {
+	i := sort.Search(len(r), func(i int) bool { return r[i].last >= x })
+	return i < len(r) && r[i].start <= x
+}
+
+// add and remove convert the runs into a plain container, RunOptimize turns
+// it back into runs when that is beneficial.
+func (r runContainer) add(x uint16) container {
+	if r.contains(x) {
+		return r
+	}
+	return r.toPlain().add(x)
+}
+
+func (r runContainer) remove(x uint16) container {
+	if !r.contains(x) {
+		return r
+	}
+	return r.toPlain().remove(x)
+}
+
+func (r runContainer) all(yield func(uint16) bool) bool {
+	for _, rn := range r {
+		for x := rn.start; ; x++ {
+			if !yield(x) {
+				return false
+			}
+			if x == rn.last {
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (r runContainer) clone() container {
+	return append(runContainer(nil), r...)
+}
+
+func (r runContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{n: r.card()}
+	for _, rn := range r {
+		b.setRange(rn.start, rn.last)
+	}
+	return b
+}
+
+// toPlain converts r into an array or a bitmap, depending on the cardinality.
+func (r runContainer) toPlain() container {
+	if r.card() <= arrayMaxSize {
+		a := make(arrayContainer, 0, r.card())
+		r.all(func(x uint16) bool {
+			a = append(a, x)
+			return true
+		})
+		return a
+	}
+	return r.toBitmap()
+}
+
+// toBitmap returns c as a bitmap, which must not be modified.
+func toBitmap(c container) *bitmapContainer {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		return c
+	case arrayContainer:
+		return c.toBitmap()
+	default:
+		return c.(runContainer).toBitmap()
+	}
+}
+
+// fromArray returns the values as an array container, or as a bitmap if
+// there are too many of them.
+func fromArray(a arrayContainer) container {
+	if len(a) > arrayMaxSize {
+		return a.toBitmap()
+	}
+	return a
+}
+
+// countRuns returns the number of runs needed to represent c.
+func countRuns(c container) int {
+	if r, ok := c.(runContainer); ok {
+		return len(r)
+	}
+	n := 0
+	next := -1
+	c.all(func(x uint16) bool {
+		if int(x) != next {
+			n++
+		}
+		next = int(x) + 1
+		return true
+	})
+	return n
+}
+
+// optimize returns the smallest representation of c.
+func optimize(c container) container {
+	n := c.card()
+	runs := countRuns(c)
+	run_size, plain_size := 2+4*runs, 2*n
+	if n > arrayMaxSize {
+		plain_size = 8 * bitmapWords
+	}
+	switch {
+	case run_size < plain_size:
+		if r, ok := c.(runContainer); ok {
+			return r
+		}
+		r := make(runContainer, 0, runs)
+		c.all(func(x uint16) bool {
+			if k := len(r) - 1; k >= 0 && r[k].last+1 == x {
+				r[k].last = x
+			} else {
+				r = append(r, run16{x, x})
+			}
+			return true
+		})
+		return r
+	case n > arrayMaxSize:
+		return toBitmap(c)
+	default:
+		if a, ok := c.(arrayContainer); ok {
+			return a
+		}
+		a := make(arrayContainer, 0, n)
+		c.all(func(x uint16) bool {
+			a = append(a, x)
+			return true
+		})
+		return a
+	}
+}
+
+// intersectRuns returns the intersection of two run containers.
+func intersectRuns(a, b runContainer) runContainer {
+	var r runContainer
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, last := max(a[i].start, b[j].start), min(a[i].last, b[j].last)
+		if k := len(r) - 1; start <= last && k >= 0 && r[k].last+1 == start {
+			r[k].last = last
+		} else if start <= last {
+			r = append(r, run16{start, last})
+		}
+		if a[i].last < b[j].last {
+			i++
+		} else {
+			j++
+		}
+	}
+	return r
+}
+
+// filter returns the values of a that are (keep == true) or are not (keep ==
+// false) in c.
+func filter(a arrayContainer, c container, keep bool) arrayContainer {
+	var r arrayContainer
+	if b, ok := c.(arrayContainer); ok {
+		i, j := 0, 0
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] < b[j]:
+				if !keep {
+					r = append(r, a[i])
+				}
+				i++
+			case a[i] > b[j]:
+				j++
+			default:
+				if keep {
+					r = append(r, a[i])
+				}
+				i++
+				j++
+			}
+		}
+		if !keep {
+			r = append(r, a[i:]...)
+		}
+		return r
+	}
+	for _, x := range a {
+		if c.contains(x) == keep {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+func andContainers(a, b container) container {
+	if aa, ok := a.(arrayContainer); ok {
+		return filter(aa, b, true)
+	}
+	if ba, ok := b.(arrayContainer); ok {
+		return filter(ba, a, true)
+	}
+	ar, a_runs := a.(runContainer)
+	br, b_runs := b.(runContainer)
+	if a_runs && b_runs {
+		return intersectRuns(ar, br)
+	}
+	r := toBitmap(a).clone().(*bitmapContainer)
+	bb := toBitmap(b)
+	for i := range r.w {
+		r.w[i] &= bb.w[i]
+	}
+	return r.normalize()
+}
+
+// andCardinality returns the number of values that are both in a and b. It
+// counts them without allocating, so that the predicates and
+// IntersectionCount of Roaring do not build any intermediate containers.
+func andCardinality(a, b container) int {
+	switch ac := a.(type) {
+	case arrayContainer:
+		if bc, ok := b.(arrayContainer); ok {
+			return andArraysCardinality(ac, bc)
+		}
+		return containedCount(ac, b)
+	case runContainer:
+		switch bc := b.(type) {
+		case arrayContainer:
+			return containedCount(bc, a)
+		case runContainer:
+			return andRunsCardinality(ac, bc)
+		case *bitmapContainer:
+			return bc.runsCardinality(ac)
+		}
+	case *bitmapContainer:
+		switch bc := b.(type) {
+		case arrayContainer:
+			return containedCount(bc, a)
+		case runContainer:
+			return ac.runsCardinality(bc)
+		case *bitmapContainer:
+			n := 0
+			for i := range ac.w {
+				n += bits.OnesCount64(ac.w[i] & bc.w[i])
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+// andArraysCardinality counts the common values of a and b with a merge walk.
+func andArraysCardinality(a, b arrayContainer) int {
+	n := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			n++
+			i++
+			j++
+		}
+	}
+	return n
+}
+
+// containedCount counts the values of a that are in c.
+func containedCount(a arrayContainer, c container) int {
+	n := 0
+	for _, x := range a {
+		if c.contains(x) {
+			n++
+		}
+	}
+	return n
+}
+
+// andRunsCardinality sums the lengths of the overlaps between the runs of a
+// and b.
+func andRunsCardinality(a, b runContainer) int {
+	n := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if start, last := max(a[i].start, b[j].start), min(a[i].last, b[j].last); start <= last {
+			n += int(last-start) + 1
+		}
+		if a[i].last < b[j].last {
+			i++
+		} else {
+			j++
+		}
+	}
+	return n
+}
+
+// runsCardinality counts the bits of b that are set within the runs of r.
+func (b *bitmapContainer) runsCardinality(r runContainer) int {
+	n := 0
+	for _, rn := range r {
+		first_word, last_word := rn.start/64, rn.last/64
+		first_mask := ^uint64(0) << (rn.start % 64)
+		last_mask := ^uint64(0) >> (63 - rn.last%64)
+		if first_word == last_word {
+			n += bits.OnesCount64(b.w[first_word] & first_mask & last_mask)
+			continue
+		}
+		n += bits.OnesCount64(b.w[first_word] & first_mask)
+		for i := first_word + 1; i < last_word; i++ {
+			n += bits.OnesCount64(b.w[i])
+		}
+		n += bits.OnesCount64(b.w[last_word] & last_mask)
+	}
+	return n
+}
+
+func orContainers(a, b container) container {
+	aa, a_array := a.(arrayContainer)
+	ba, b_array := b.(arrayContainer)
+	if a_array && b_array {
+		r := make(arrayContainer, 0, len(aa)+len(ba))
+		i, j := 0, 0
+		for i < len(aa) && j < len(ba) {
+			switch {
+			case aa[i] < ba[j]:
+				r = append(r, aa[i])
+				i++
+			case aa[i] > ba[j]:
+				r = append(r, ba[j])
+				j++
+			default:
+				r = append(r, aa[i])
+				i++
+				j++
+			}
+		}
+		r = append(r, aa[i:]...)
+		return fromArray(append(r, ba[j:]...))
+	}
+	r := toBitmap(a).clone().(*bitmapContainer)
+	if b_array {
+		for _, x := range ba {
+			r.w[x/64] |= 1 << (x % 64)
+		}
+	} else {
+		bb := toBitmap(b)
+		for i := range r.w {
+			r.w[i] |= bb.w[i]
+		}
+	}
+	return r.normalize()
+}
+
+func andNotContainers(a, b container) container {
+	if aa, ok := a.(arrayContainer); ok {
+		return filter(aa, b, false)
+	}
+	r := toBitmap(a).clone().(*bitmapContainer)
+	if ba, ok := b.(arrayContainer); ok {
+		for _, x := range ba {
+			r.w[x/64] &^= 1 << (x % 64)
+		}
+	} else {
+		bb := toBitmap(b)
+		for i := range r.w {
+			r.w[i] &^= bb.w[i]
+		}
+	}
+	return r.normalize()
+}
+
+func xorContainers(a, b container) container {
+	aa, a_array := a.(arrayContainer)
+	ba, b_array := b.(arrayContainer)
+	if a_array && b_array {
+		r := make(arrayContainer, 0, len(aa)+len(ba))
+		i, j := 0, 0
+		for i < len(aa) && j < len(ba) {
+			switch {
+			case aa[i] < ba[j]:
+				r = append(r, aa[i])
+				i++
+			case aa[i] > ba[j]:
+				r = append(r, ba[j])
+				j++
+			default:
+				i++
+				j++
+			}
+		}
+		r = append(r, aa[i:]...)
+		return fromArray(append(r, ba[j:]...))
+	}
+	if a_array {
+		a, ba, b_array = b, aa, true
+	}
+	r := toBitmap(a).clone().(*bitmapContainer)
+	if b_array {
+		for _, x := range ba {
+			r.w[x/64] ^= 1 << (x % 64)
+		}
+	} else {
+		bb := toBitmap(b)
+		for i := range r.w {
+			r.w[i] ^= bb.w[i]
+		}
+	}
+	return r.normalize()
+}
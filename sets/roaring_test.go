@@ -0,0 +1,273 @@
+package sets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// randomRoaring returns a set with sparse, dense and consecutive values
+// spread over a few containers, together with the equivalent map-based set.
+func randomRoaring(rnd *rand.Rand) (*Roaring, map[uint32]struct{}) {
+	s := map[uint32]struct{}{}
+	for hi := uint32(0); hi < 4; hi++ {
+		base := hi << 16
+		switch rnd.Intn(4) {
+		case 0: // sparse
+			for i := rnd.Intn(100); i > 0; i-- {
+				s[base|uint32(rnd.Intn(1<<16))] = struct{}{}
+			}
+		case 1: // dense
+			for i := 5000 + rnd.Intn(5000); i > 0; i-- {
+				s[base|uint32(rnd.Intn(1<<14))] = struct{}{}
+			}
+		case 2: // runs
+			for i := rnd.Intn(5); i > 0; i-- {
+				start := rnd.Intn(1 << 16)
+				for v := start; v < min(start+rnd.Intn(3000), 1<<16); v++ {
+					s[base|uint32(v)] = struct{}{}
+				}
+			}
+		}
+	}
+	r := RoaringFrom(s)
+	if rnd.Intn(2) == 0 {
+		r.RunOptimize()
+	}
+	return r, s
+}
+
+func TestRoaring(t *testing.T) {
+	var r Roaring
+	if r.Count() != 0 || r.Contains(0) {
+		t.Fatalf("zero value is not empty")
+	}
+	if _, ok := r.Min(); ok {
+		t.Errorf("Min() of an empty set")
+	}
+	r.Insert(5, 1<<20, 3, 1<<20+1, 5)
+	if got := r.Keys(); !slices.Equal(got, []uint32{3, 5, 1 << 20, 1<<20 + 1}) {
+		t.Errorf("Keys() = %v", got)
+	}
+	if lo, _ := r.Min(); lo != 3 {
+		t.Errorf("Min() = %d", lo)
+	}
+	if hi, _ := r.Max(); hi != 1<<20+1 {
+		t.Errorf("Max() = %d", hi)
+	}
+	r.Remove(1<<20, 1<<20+1, 7)
+	if !r.Equal(NewRoaring(3, 5)) || len(r.keys) != 1 {
+		t.Errorf("Remove() left %v in %d containers", r.Keys(), len(r.keys))
+	}
+	c := r.Clone()
+	c.Insert(9)
+	if r.Contains(9) {
+		t.Errorf("Clone() shares storage")
+	}
+	r.Clear()
+	if r.Count() != 0 {
+		t.Errorf("Clear() left %v", r.Keys())
+	}
+}
+
+func TestRoaringContainers(t *testing.T) {
+	var r Roaring
+	for v := uint32(0); v < 10000; v++ {
+		r.Insert(v)
+	}
+	if _, ok := r.cs[0].(*bitmapContainer); !ok {
+		t.Errorf("dense container is %T", r.cs[0])
+	}
+	if hi, _ := r.Max(); hi != 9999 {
+		t.Errorf("Max() = %d", hi)
+	}
+	r.RunOptimize()
+	if rc, ok := r.cs[0].(runContainer); !ok || len(rc) != 1 {
+		t.Errorf("consecutive values are stored as %T", r.cs[0])
+	}
+	r.Remove(5000)
+	if r.Count() != 9999 || r.Contains(5000) || !r.Contains(5001) {
+		t.Errorf("Remove() from runs is wrong")
+	}
+	for v := uint32(0); v < 6000; v++ {
+		r.Remove(v)
+	}
+	if _, ok := r.cs[0].(arrayContainer); !ok || r.Count() != 4000 {
+		t.Errorf("sparse container is %T with %d values", r.cs[0], r.Count())
+	}
+}
+
+func TestRoaringAlgebra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		r1, s1 := randomRoaring(rnd)
+		r2, s2 := randomRoaring(rnd)
+		if !Equal(r1.Set(), s1) || r1.Count() != len(s1) {
+			t.Fatalf("RoaringFrom() lost values")
+		}
+		check := func(name string, got *Roaring, want map[uint32]struct{}) {
+			t.Helper()
+			if got.Count() != len(want) || !Equal(got.Set(), want) {
+				t.Fatalf("%s() has %d values, want %d", name, got.Count(), len(want))
+			}
+		}
+		check("Union", r1.Union(r2), Union(s1, s2))
+		check("Intersection", r1.Intersection(r2), Intersection(s1, s2))
+		check("Difference", r1.Difference(r2), Difference(s1, s2))
+		check("SymmetricDifference", r1.SymmetricDifference(r2), SymmetricDifference(s1, s2))
+
+		in_place := r1.Clone()
+		in_place.Merge(r2)
+		in_place.Subtract(r2)
+		check("Subtract", in_place, Difference(s1, s2))
+
+		if got, want := r1.IntersectionCount(r2), len(Intersection(s1, s2)); got != want {
+			t.Fatalf("IntersectionCount() = %d, want %d", got, want)
+		}
+		if got, want := r1.IsDisjoint(r2), IsDisjoint(s1, s2); got != want {
+			t.Fatalf("IsDisjoint() = %v, want %v", got, want)
+		}
+		if got, want := r1.IsSubset(r2), IsSubset(s1, s2); got != want {
+			t.Fatalf("IsSubset() = %v, want %v", got, want)
+		}
+		if !r1.Intersection(r2).IsSubset(r1) || !r1.Union(r2).Equal(r2.Union(r1)) {
+			t.Fatalf("algebra laws do not hold")
+		}
+		if !Equal(r1.Set(), s1) || !Equal(r2.Set(), s2) {
+			t.Fatalf("operands were modified")
+		}
+	}
+}
+
+func TestRoaringCountingDoesNotAllocate(t *testing.T) {
+	// one container of each type for the same high bits
+	var array, bitmap, runs Roaring
+	for v := uint32(0); v < 1<<16; v += 17 {
+		array.Insert(v)
+	}
+	for v := uint32(0); v < 1<<16; v += 3 {
+		bitmap.Insert(v)
+	}
+	for v := uint32(100); v < 30000; v++ {
+		runs.Insert(v)
+	}
+	for v := uint32(40000); v < 40100; v++ {
+		runs.Insert(v)
+	}
+	runs.RunOptimize()
+	if _, ok := array.cs[0].(arrayContainer); !ok {
+		t.Fatalf("array container is %T", array.cs[0])
+	}
+	if _, ok := bitmap.cs[0].(*bitmapContainer); !ok {
+		t.Fatalf("bitmap container is %T", bitmap.cs[0])
+	}
+	if _, ok := runs.cs[0].(runContainer); !ok {
+		t.Fatalf("run container is %T", runs.cs[0])
+	}
+
+	all := []*Roaring{&array, &bitmap, &runs}
+	for _, a := range all {
+		for _, b := range all {
+			if got, want := a.IntersectionCount(b), a.Intersection(b).Count(); got != want {
+				t.Errorf("%T x %T: IntersectionCount() = %d, want %d", a.cs[0], b.cs[0], got, want)
+			}
+			allocs := testing.AllocsPerRun(10, func() {
+				a.IntersectionCount(b)
+				a.IsSubset(b)
+				a.IsDisjoint(b)
+				a.Equal(b)
+			})
+			if allocs != 0 {
+				t.Errorf("%T x %T: %v allocations, want 0", a.cs[0], b.cs[0], allocs)
+			}
+		}
+	}
+}
+
+func TestRoaringSerialization(t *testing.T) {
+	tests := []struct {
+		name string
+		r    func() *Roaring
+		want string
+	}{
+		{"empty", func() *Roaring { return NewRoaring() }, "3a30000000000000"},
+		{"array", func() *Roaring { return NewRoaring(1, 2, 3) },
+			"3a300000" + "01000000" + "00000200" + "10000000" + "010002000300"},
+		{"runs", func() *Roaring {
+			r := NewRoaring()
+			for v := uint32(0); v < 10; v++ {
+				r.Insert(v)
+			}
+			r.RunOptimize()
+			return r
+		}, "3b300000" + "01" + "00000900" + "0100" + "00000900"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r()
+			data, err := r.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(data); got != tt.want {
+				t.Errorf("MarshalBinary() = %s, want %s", got, tt.want)
+			}
+			var back Roaring
+			if err := back.UnmarshalBinary(data); err != nil || !back.Equal(r) {
+				t.Errorf("UnmarshalBinary() = %v, %v", back.Keys(), err)
+			}
+		})
+	}
+
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		r, s := randomRoaring(rnd)
+		var buf bytes.Buffer
+		n, err := r.WriteTo(&buf)
+		if err != nil || n != int64(buf.Len()) {
+			t.Fatalf("WriteTo() = %d, %v", n, err)
+		}
+		buf.WriteString("trailing data")
+		var back Roaring
+		if m, err := back.ReadFrom(&buf); err != nil || m != n {
+			t.Fatalf("ReadFrom() = %d, %v, want %d", m, err, n)
+		}
+		if !Equal(back.Set(), s) {
+			t.Fatalf("ReadFrom() does not match the original")
+		}
+		if buf.String() != "trailing data" {
+			t.Fatalf("ReadFrom() consumed extra data")
+		}
+	}
+}
+
+func TestRoaringInvalidData(t *testing.T) {
+	valid, _ := NewRoaring(1, 2, 3).MarshalBinary()
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"bad cookie", []byte{1, 2, 3, 4}, ErrRoaringFormat},
+		{"truncated", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"trailing data", append(slices.Clone(valid), 0), ErrRoaringFormat},
+		{"unsorted", func() []byte {
+			d := slices.Clone(valid)
+			d[16], d[18] = 3, 1
+			return d
+		}(), ErrRoaringFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Roaring
+			if err := r.UnmarshalBinary(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("UnmarshalBinary() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
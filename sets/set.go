@@ -1,5 +1,7 @@
 package sets
 
+import "golang.org/x/exp/slices"
+
 // Sets contain unique elements (keys). Effectively sets are implemented as
 // key-only maps of empty structs: set[K] = map[K]struct{}
 
@@ -84,6 +86,23 @@ func Union[S ~map[K]struct{}, K comparable](s1 S, s2 S) S {
 	return r
 }
 
+// UnionAll combines keys from all the sets into one set.
+// Returns ss[0] ∪ ss[1] ∪ ... ∪ ss[n-1].
+//
+// The result is preallocated to hold the keys from all the sets, so that it
+// never grows while they are copied.
+func UnionAll[S ~map[K]struct{}, K comparable](ss ...S) S {
+	total := 0
+	for _, s := range ss {
+		total += len(s)
+	}
+	r := make(S, total)
+	for _, s := range ss {
+		Merge(r, s)
+	}
+	return r
+}
+
 // Merge inserts keys from src into the dst.
 // Effectively, dst = dst ∪ src.
 func Merge[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](dst S1, src S2) {
@@ -122,6 +141,7 @@ func Intersection[S ~map[K]struct{}, K comparable](s1 S, s2 S) S {
 	}
 	if n2 < n {
 		n = n2
+		s1, s2 = s2, s1
 	}
 	r := make(S, n)
 	for k := range s1 {
@@ -132,6 +152,37 @@ func Intersection[S ~map[K]struct{}, K comparable](s1 S, s2 S) S {
 	return r
 }
 
+// IntersectionAll returns keys that exist in all the sets.
+// Returns ss[0] ∩ ss[1] ∩ ... ∩ ss[n-1].
+//
+// The sets are evaluated smallest first: the result is preallocated for the
+// smallest set and then narrowed down by the larger ones. The evaluation stops
+// as soon as the intermediate result becomes empty.
+func IntersectionAll[S ~map[K]struct{}, K comparable](ss ...S) S {
+	switch len(ss) {
+	case 0:
+		return S{}
+	case 1:
+		return Clone(ss[0])
+	}
+	ordered := slices.Clone(ss)
+	slices.SortFunc(ordered, func(a, b S) bool {
+		return len(a) < len(b)
+	})
+	r := Intersection(ordered[0], ordered[1])
+	for _, s := range ordered[2:] {
+		if len(r) == 0 {
+			break
+		}
+		for k := range r {
+			if _, ok := s[k]; !ok {
+				delete(r, k)
+			}
+		}
+	}
+	return r
+}
+
 // Intersect removes keys from dst that are not contained in src.
 // Effectively, dst = dst ∩ src
 func Intersect[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](dst S1, src S2) {
@@ -146,6 +197,90 @@ func Intersect[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](dst S1, src
 	}
 }
 
+// SymmetricDifference returns the keys that are contained in either s1 or s2,
+// but not in both.
+// Effectively: s1 ∆ s2
+func SymmetricDifference[S1, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) S1 {
+	r := S1{}
+	for k := range s1 {
+		if _, ok := s2[k]; !ok {
+			r[k] = struct{}{}
+		}
+	}
+	for k := range s2 {
+		if _, ok := s1[k]; !ok {
+			r[k] = struct{}{}
+		}
+	}
+	return r
+}
+
+// IsSubset reports whether all the keys in s1 are also contained in s2.
+// Effectively: s1 ⊆ s2
+func IsSubset[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) bool {
+	if len(s1) > len(s2) {
+		return false
+	}
+	for k := range s1 {
+		if _, ok := s2[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubset reports whether all the keys in s1 are also contained in s2
+// and s2 has at least one key that is not in s1.
+// Effectively: s1 ⊂ s2
+func IsProperSubset[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) bool {
+	return len(s1) < len(s2) && IsSubset(s1, s2)
+}
+
+// IsSuperset reports whether all the keys in s2 are also contained in s1.
+// Effectively: s1 ⊇ s2
+func IsSuperset[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) bool {
+	return IsSubset(s2, s1)
+}
+
+// IsProperSuperset reports whether all the keys in s2 are also contained in
+// s1 and s1 has at least one key that is not in s2.
+// Effectively: s1 ⊃ s2
+func IsProperSuperset[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) bool {
+	return IsProperSubset(s2, s1)
+}
+
+// IsDisjoint reports whether s1 and s2 have no keys in common.
+// Effectively: s1 ∩ s2 = ∅
+func IsDisjoint[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](s1 S1, s2 S2) bool {
+	if len(s2) < len(s1) {
+		for k := range s2 {
+			if _, ok := s1[k]; ok {
+				return false
+			}
+		}
+		return true
+	}
+	for k := range s1 {
+		if _, ok := s2[k]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricSubtract removes the keys that are contained in both dst and src
+// from the dst and inserts the keys from src that are not in dst.
+// Effectively, dst = dst ∆ src.
+func SymmetricSubtract[S1 ~map[K]struct{}, S2 ~map[K]struct{}, K comparable](dst S1, src S2) {
+	for k := range src {
+		if _, ok := dst[k]; ok {
+			delete(dst, k)
+		} else {
+			dst[k] = struct{}{}
+		}
+	}
+}
+
 // Keys returns the keys from s as a slice. The keys will be in an
 // indeterminate order.
 func Keys[S ~map[K]struct{}, K comparable](s S) []K {
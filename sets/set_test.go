@@ -137,3 +137,189 @@ func TestIntersect(t *testing.T) {
 		})
 	}
 }
+
+func TestSymmetricDifference(t *testing.T) {
+	tests := []struct {
+		s1   map[int]struct{}
+		s2   map[int]struct{}
+		want map[int]struct{}
+	}{
+		{empty, empty, empty},
+		{set(1), empty, set(1)},
+		{empty, set(1), set(1)},
+		{set(1), set(1), empty},
+		{set(1), set(2), set(1, 2)},
+		{set(1, 2, 3), set(2, 3, 4), set(1, 4)},
+	}
+	for _, tt := range tests {
+		name := to_string(tt.s1) + " ∆ " + to_string(tt.s2)
+		t.Run(name, func(t *testing.T) {
+			got := SymmetricDifference(tt.s1, tt.s2)
+			if !Equal(tt.want, got) {
+				t.Errorf("SymmetricDifference() = %s, want %s", to_string(got), to_string(tt.want))
+			}
+		})
+	}
+
+	// the result has the type of the first argument
+	var got Set[int] = SymmetricDifference(Of(1, 2), set(2, 3))
+	if !Equal(got, set(1, 3)) {
+		t.Errorf("SymmetricDifference() = %s, want %s", to_string(got), to_string(set(1, 3)))
+	}
+}
+
+func TestSymmetricSubtract(t *testing.T) {
+	tests := []struct {
+		dst  map[int]struct{}
+		src  map[int]struct{}
+		want map[int]struct{}
+	}{
+		{empty, empty, empty},
+		{set(1), empty, set(1)},
+		{empty, set(1), set(1)},
+		{set(1), set(1), empty},
+		{set(1), set(2), set(1, 2)},
+		{set(1, 2, 3), set(2, 3, 4), set(1, 4)},
+	}
+	for _, tt := range tests {
+		name := to_string(tt.dst) + " ∆ " + to_string(tt.src)
+		t.Run(name, func(t *testing.T) {
+			got := Clone(tt.dst)
+			SymmetricSubtract(got, tt.src)
+			if !Equal(tt.want, got) {
+				t.Errorf("SymmetricSubtract() = %s, want %s", to_string(got), to_string(tt.want))
+			}
+		})
+	}
+}
+
+func TestIsSubset(t *testing.T) {
+	tests := []struct {
+		s1   map[int]struct{}
+		s2   map[int]struct{}
+		want bool
+	}{
+		{empty, empty, true},
+		{empty, set(1), true},
+		{set(1), empty, false},
+		{set(1), set(1), true},
+		{set(1), set(1, 2), true},
+		{set(1, 3), set(1, 2), false},
+	}
+	for _, tt := range tests {
+		name := to_string(tt.s1) + " ⊆ " + to_string(tt.s2)
+		t.Run(name, func(t *testing.T) {
+			if got := IsSubset(tt.s1, tt.s2); got != tt.want {
+				t.Errorf("IsSubset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		s1             map[int]struct{}
+		s2             map[int]struct{}
+		properSubset   bool
+		superset       bool
+		properSuperset bool
+		disjoint       bool
+	}{
+		{empty, empty, false, true, false, true},
+		{empty, set(1), true, false, false, true},
+		{set(1), empty, false, true, true, true},
+		{set(1), set(1), false, true, false, false},
+		{set(1), set(1, 2), true, false, false, false},
+		{set(1, 2), set(1), false, true, true, false},
+		{set(1, 2), set(3, 4), false, false, false, true},
+		{set(1, 2), set(2, 3), false, false, false, false},
+	}
+	for _, tt := range tests {
+		name := to_string(tt.s1) + " " + to_string(tt.s2)
+		t.Run(name, func(t *testing.T) {
+			if got := IsProperSubset(tt.s1, tt.s2); got != tt.properSubset {
+				t.Errorf("IsProperSubset() = %v, want %v", got, tt.properSubset)
+			}
+			if got := IsSuperset(tt.s1, tt.s2); got != tt.superset {
+				t.Errorf("IsSuperset() = %v, want %v", got, tt.superset)
+			}
+			if got := IsProperSuperset(tt.s1, tt.s2); got != tt.properSuperset {
+				t.Errorf("IsProperSuperset() = %v, want %v", got, tt.properSuperset)
+			}
+			if got := IsDisjoint(tt.s1, tt.s2); got != tt.disjoint {
+				t.Errorf("IsDisjoint() = %v, want %v", got, tt.disjoint)
+			}
+		})
+	}
+}
+
+func TestUnionAll(t *testing.T) {
+	tests := []struct {
+		ss   []map[int]struct{}
+		want map[int]struct{}
+	}{
+		{nil, empty},
+		{[]map[int]struct{}{empty}, empty},
+		{[]map[int]struct{}{set(1)}, set(1)},
+		{[]map[int]struct{}{set(1), set(2), set(3)}, set(1, 2, 3)},
+		{[]map[int]struct{}{set(1, 2), empty, set(2, 3, 4, 5)}, set(1, 2, 3, 4, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(to_string(tt.want), func(t *testing.T) {
+			got := UnionAll(tt.ss...)
+			if !Equal(tt.want, got) {
+				t.Errorf("UnionAll() = %s, want %s", to_string(got), to_string(tt.want))
+			}
+		})
+	}
+}
+
+func TestUnionAllPreallocates(t *testing.T) {
+	ss := make([]map[int]struct{}, 8)
+	for i := range ss {
+		ss[i] = map[int]struct{}{}
+		for k := 0; k < 100; k++ {
+			ss[i][i*50+k] = struct{}{}
+		}
+	}
+	// filling a map that is created with the total size does not grow it
+	want := testing.AllocsPerRun(10, func() {
+		r := make(map[int]struct{}, 800)
+		for _, s := range ss {
+			Merge(r, s)
+		}
+	})
+	if got := testing.AllocsPerRun(10, func() { UnionAll(ss...) }); got > want {
+		t.Errorf("UnionAll() allocates %v times, want %v", got, want)
+	}
+}
+
+func TestIntersectionAll(t *testing.T) {
+	tests := []struct {
+		ss   []map[int]struct{}
+		want map[int]struct{}
+	}{
+		{nil, empty},
+		{[]map[int]struct{}{set(1, 2)}, set(1, 2)},
+		{[]map[int]struct{}{set(1, 2), set(2, 3)}, set(2)},
+		{[]map[int]struct{}{set(1, 2, 3, 4), set(2, 3, 4), set(3, 4, 5)}, set(3, 4)},
+		{[]map[int]struct{}{set(1, 2, 3), empty, set(1, 2, 3)}, empty},
+		{[]map[int]struct{}{set(1, 2), set(3, 4), set(1, 2, 3, 4)}, empty},
+	}
+	for _, tt := range tests {
+		t.Run(to_string(tt.want), func(t *testing.T) {
+			got := IntersectionAll(tt.ss...)
+			if !Equal(tt.want, got) {
+				t.Errorf("IntersectionAll() = %s, want %s", to_string(got), to_string(tt.want))
+			}
+		})
+	}
+}
+
+func TestIntersectionAllDoesNotModifyInputs(t *testing.T) {
+	a, b := set(1, 2, 3), set(2, 3)
+	_ = IntersectionAll(a, b, set(3))
+	if !Equal(a, set(1, 2, 3)) || !Equal(b, set(2, 3)) {
+		t.Errorf("IntersectionAll() modified its inputs: %s, %s", to_string(a), to_string(b))
+	}
+}
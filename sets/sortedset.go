@@ -0,0 +1,248 @@
+package sets
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/tree"
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// SortedSet is a set that keeps its keys sorted. It is backed by a balanced
+// binary search tree, so that insertions, removals, lookups and
+// order-statistic queries take O(log n), and iterating in order does not
+// require sorting.
+//
+// The zero value is an empty set ready to use. A SortedSet must not be copied
+// after first use and is not safe for concurrent use.
+type SortedSet[K constraints.Ordered] struct {
+	t tree.Tree[K, struct{}]
+}
+
+// NewSortedSet returns a sorted set containing the keys.
+func NewSortedSet[K constraints.Ordered](keys ...K) *SortedSet[K] {
+	s := &SortedSet[K]{}
+	s.Insert(keys...)
+	return s
+}
+
+// SortedSetFrom returns a sorted set containing the keys from s.
+func SortedSetFrom[S ~map[K]struct{}, K constraints.Ordered](s S) *SortedSet[K] {
+	return newSortedSet(Sorted(s))
+}
+
+// newSortedSet builds a sorted set from keys that are already sorted and
+// contain no duplicates.
+func newSortedSet[K constraints.Ordered](keys []K) *SortedSet[K] {
+	return &SortedSet[K]{*tree.Build[K, struct{}](lessOrdered[K], keys, nil)}
+}
+
+func (s *SortedSet[K]) lazyInit() {
+	if s.t.Less() == nil {
+		s.t = *tree.New[K, struct{}](lessOrdered[K])
+	}
+}
+
+// Len returns the number of keys in s.
+func (s *SortedSet[K]) Len() int {
+	return s.t.Len()
+}
+
+// Contains checks if there is a key in s.
+func (s *SortedSet[K]) Contains(k K) bool {
+	_, ok := s.t.Get(k)
+	return ok
+}
+
+// Insert inserts the keys into s.
+func (s *SortedSet[K]) Insert(keys ...K) {
+	s.lazyInit()
+	for _, k := range keys {
+		s.t.Put(k, struct{}{})
+	}
+}
+
+// Remove removes the keys from s.
+func (s *SortedSet[K]) Remove(keys ...K) {
+	for _, k := range keys {
+		s.t.Delete(k)
+	}
+}
+
+// Clear removes all keys from s.
+func (s *SortedSet[K]) Clear() {
+	s.t.Clear()
+}
+
+// Clone returns a copy of s.
+func (s *SortedSet[K]) Clone() *SortedSet[K] {
+	return newSortedSet(s.Keys())
+}
+
+// Min returns the smallest key in s.
+func (s *SortedSet[K]) Min() (k K, ok bool) {
+	k, _, ok = s.t.Min()
+	return
+}
+
+// Max returns the largest key in s.
+func (s *SortedSet[K]) Max() (k K, ok bool) {
+	k, _, ok = s.t.Max()
+	return
+}
+
+// Floor returns the largest key in s that is less than or equal to k.
+func (s *SortedSet[K]) Floor(k K) (r K, ok bool) {
+	r, _, ok = s.t.Floor(k)
+	return
+}
+
+// Ceiling returns the smallest key in s that is greater than or equal to k.
+func (s *SortedSet[K]) Ceiling(k K) (r K, ok bool) {
+	r, _, ok = s.t.Ceiling(k)
+	return
+}
+
+// Predecessor returns the largest key in s that is strictly less than k.
+func (s *SortedSet[K]) Predecessor(k K) (r K, ok bool) {
+	r, _, ok = s.t.Lower(k)
+	return
+}
+
+// Successor returns the smallest key in s that is strictly greater than k.
+func (s *SortedSet[K]) Successor(k K) (r K, ok bool) {
+	r, _, ok = s.t.Higher(k)
+	return
+}
+
+// Rank returns the number of keys in s that are less than k. When k is in s,
+// this is its zero-based position in sorted order.
+func (s *SortedSet[K]) Rank(k K) int {
+	return s.t.Rank(k)
+}
+
+// Select returns the key at the zero-based position i in sorted order.
+func (s *SortedSet[K]) Select(i int) (k K, ok bool) {
+	k, _, ok = s.t.Select(i)
+	return
+}
+
+// Range returns an iterator over the keys in the half-open interval [lo, hi)
+// in sorted order.
+func (s *SortedSet[K]) Range(lo, hi K) iter.Seq[K] {
+	return keysOf(s.t.Range(lo, hi))
+}
+
+// All returns an iterator over the keys in s in sorted order.
+func (s *SortedSet[K]) All() iter.Seq[K] {
+	return keysOf(s.t.All())
+}
+
+// Backward returns an iterator over the keys in s in reverse sorted order.
+func (s *SortedSet[K]) Backward() iter.Seq[K] {
+	return keysOf(s.t.Backward())
+}
+
+// Keys returns the keys from s as a sorted slice.
+func (s *SortedSet[K]) Keys() []K {
+	r := make([]K, 0, s.Len())
+	for k := range s.t.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Set returns the keys from s as a Set, so that they can be used with the
+// rest of this package.
+func (s *SortedSet[K]) Set() Set[K] {
+	r := make(Set[K], s.Len())
+	for k := range s.t.All() {
+		r[k] = struct{}{}
+	}
+	return r
+}
+
+// Equal reports whether s and o contain the same keys.
+func (s *SortedSet[K]) Equal(o *SortedSet[K]) bool {
+	return s.Len() == o.Len() && slices.Equal(s.Keys(), o.Keys())
+}
+
+// Union returns s ∪ o. The keys of both sets are combined with a linear merge
+// and the result is built without any further comparisons.
+func (s *SortedSet[K]) Union(o *SortedSet[K]) *SortedSet[K] {
+	a, b := s.Keys(), o.Keys()
+	r := make([]K, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			r = append(r, a[i])
+			i++
+		case b[j] < a[i]:
+			r = append(r, b[j])
+			j++
+		default:
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	r = append(r, b[j:]...)
+	return newSortedSet(r)
+}
+
+// Intersection returns s ∩ o, computed with a linear merge.
+func (s *SortedSet[K]) Intersection(o *SortedSet[K]) *SortedSet[K] {
+	a, b := s.Keys(), o.Keys()
+	r := make([]K, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	return newSortedSet(r)
+}
+
+// Difference returns s - o, computed with a linear merge.
+func (s *SortedSet[K]) Difference(o *SortedSet[K]) *SortedSet[K] {
+	a, b := s.Keys(), o.Keys()
+	r := make([]K, 0, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			r = append(r, a[i])
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	return newSortedSet(r)
+}
+
+func keysOf[K any](seq iter.Seq2[K, struct{}]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func lessOrdered[K constraints.Ordered](a, b K) bool {
+	return a < b
+}
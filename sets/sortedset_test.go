@@ -0,0 +1,123 @@
+package sets
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSortedSetQueries(t *testing.T) {
+	s := NewSortedSet(50, 10, 40, 20, 30)
+	s.Insert(10, 60)
+	s.Remove(50, 70)
+
+	if got, want := s.Keys(), []int{10, 20, 30, 40, 60}; !slices.Equal(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+
+	type query struct {
+		name string
+		fn   func(int) (int, bool)
+	}
+	queries := []query{
+		{"Floor", s.Floor},
+		{"Ceiling", s.Ceiling},
+		{"Predecessor", s.Predecessor},
+		{"Successor", s.Successor},
+	}
+	tests := []struct {
+		k    int
+		want [4]int // -1 means not found
+	}{
+		{5, [4]int{-1, 10, -1, 10}},
+		{10, [4]int{10, 10, -1, 20}},
+		{25, [4]int{20, 30, 20, 30}},
+		{40, [4]int{40, 40, 30, 60}},
+		{60, [4]int{60, 60, 40, -1}},
+		{65, [4]int{60, -1, 60, -1}},
+	}
+	for _, tt := range tests {
+		for i, q := range queries {
+			got, ok := q.fn(tt.k)
+			if !ok {
+				got = -1
+			}
+			if got != tt.want[i] {
+				t.Errorf("%s(%d) = %d, want %d", q.name, tt.k, got, tt.want[i])
+			}
+		}
+	}
+
+	if r := s.Rank(30); r != 2 {
+		t.Errorf("Rank(30) = %d, want 2", r)
+	}
+	if r := s.Rank(35); r != 3 {
+		t.Errorf("Rank(35) = %d, want 3", r)
+	}
+	if k, ok := s.Select(3); !ok || k != 40 {
+		t.Errorf("Select(3) = %d, %v, want 40, true", k, ok)
+	}
+
+	var got []int
+	for k := range s.Range(15, 40) {
+		got = append(got, k)
+	}
+	if want := []int{20, 30}; !slices.Equal(got, want) {
+		t.Errorf("Range(15, 40) = %v, want %v", got, want)
+	}
+}
+
+func TestSortedSetZeroValue(t *testing.T) {
+	var s SortedSet[int]
+	if s.Len() != 0 || s.Contains(1) || len(s.Keys()) != 0 {
+		t.Errorf("zero value is not empty")
+	}
+	if _, ok := s.Min(); ok {
+		t.Errorf("Min() = _, true for an empty set")
+	}
+	for k := range s.All() {
+		t.Errorf("All() yielded %d for an empty set", k)
+	}
+	s.Remove(1)
+	if u := s.Union(NewSortedSet(2, 1)); !u.Equal(NewSortedSet(1, 2)) {
+		t.Errorf("Union() = %v", u.Keys())
+	}
+
+	s.Insert(3, 1, 2)
+	if got, want := s.Keys(), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	s.Clear()
+	s.Insert(5, 4)
+	if got, want := s.Keys(), []int{4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Keys() after Clear() = %v, want %v", got, want)
+	}
+}
+
+func TestSortedSetAlgebra(t *testing.T) {
+	tests := []struct {
+		s1, s2                          map[int]struct{}
+		union, intersection, difference map[int]struct{}
+	}{
+		{empty, empty, empty, empty, empty},
+		{set(1), empty, set(1), empty, set(1)},
+		{empty, set(1), set(1), empty, empty},
+		{set(1, 2, 3), set(2, 3, 4), set(1, 2, 3, 4), set(2, 3), set(1)},
+		{set(1, 3, 5), set(2, 4, 6), set(1, 2, 3, 4, 5, 6), empty, set(1, 3, 5)},
+	}
+	for _, tt := range tests {
+		name := to_string(tt.s1) + " " + to_string(tt.s2)
+		t.Run(name, func(t *testing.T) {
+			a, b := SortedSetFrom(tt.s1), SortedSetFrom(tt.s2)
+			if got := a.Union(b); !Equal(got.Set(), tt.union) || !slices.IsSorted(got.Keys()) {
+				t.Errorf("Union() = %v, want %s", got.Keys(), to_string(tt.union))
+			}
+			if got := a.Intersection(b); !Equal(got.Set(), tt.intersection) {
+				t.Errorf("Intersection() = %v, want %s", got.Keys(), to_string(tt.intersection))
+			}
+			if got := a.Difference(b); !Equal(got.Set(), tt.difference) {
+				t.Errorf("Difference() = %v, want %s", got.Keys(), to_string(tt.difference))
+			}
+		})
+	}
+}
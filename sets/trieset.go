@@ -0,0 +1,99 @@
+package sets
+
+import (
+	"iter"
+
+	"github.com/adnsv/go-exp/internal/trie"
+)
+
+// TrieSet is a set of strings backed by a radix tree. It answers prefix
+// queries: WithPrefix iterates over the keys that start with a prefix without
+// scanning the rest of the set, and LongestPrefix finds the longest key that
+// is a prefix of a string. Keys are always iterated in sorted order.
+//
+// The zero value is an empty set ready to use. A TrieSet is not safe for
+// concurrent use.
+type TrieSet struct {
+	t trie.Trie[struct{}]
+}
+
+// NewTrieSet returns a TrieSet containing the keys.
+func NewTrieSet(keys ...string) *TrieSet {
+	s := &TrieSet{}
+	s.Insert(keys...)
+	return s
+}
+
+// TrieSetFrom returns a TrieSet with the keys of s.
+func TrieSetFrom[S ~map[string]struct{}](s S) *TrieSet {
+	r := &TrieSet{}
+	for k := range s {
+		r.t.Put(k, struct{}{})
+	}
+	return r
+}
+
+// Len returns the number of keys in s.
+func (s *TrieSet) Len() int {
+	return s.t.Len()
+}
+
+// Contains checks if there is a key in s.
+func (s *TrieSet) Contains(k string) bool {
+	_, ok := s.t.Get(k)
+	return ok
+}
+
+// Insert inserts the keys into s.
+func (s *TrieSet) Insert(keys ...string) {
+	for _, k := range keys {
+		s.t.Put(k, struct{}{})
+	}
+}
+
+// Remove removes the keys from s.
+func (s *TrieSet) Remove(keys ...string) {
+	for _, k := range keys {
+		s.t.Delete(k)
+	}
+}
+
+// Clear removes all keys from s.
+func (s *TrieSet) Clear() {
+	s.t.Clear()
+}
+
+// WithPrefix returns an iterator over the keys of s that start with prefix,
+// in order.
+func (s *TrieSet) WithPrefix(prefix string) iter.Seq[string] {
+	return keysOf(s.t.WithPrefix(prefix))
+}
+
+// LongestPrefix returns the longest key in s that is a prefix of str.
+func (s *TrieSet) LongestPrefix(str string) (k string, ok bool) {
+	k, _, ok = s.t.LongestPrefix(str)
+	return
+}
+
+// All returns an iterator over the keys of s in order.
+func (s *TrieSet) All() iter.Seq[string] {
+	return keysOf(s.t.All())
+}
+
+// Keys returns the keys of s as a sorted slice.
+func (s *TrieSet) Keys() []string {
+	r := make([]string, 0, s.Len())
+	for k := range s.t.All() {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Set returns the keys of s as a plain set.
+func (s *TrieSet) Set() Set[string] {
+	r := make(Set[string], s.Len())
+	for k := range s.t.All() {
+		r[k] = struct{}{}
+	}
+	return r
+}
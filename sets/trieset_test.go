@@ -0,0 +1,69 @@
+package sets
+
+import (
+	"iter"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func collect(seq iter.Seq[string]) []string {
+	var r []string
+	for k := range seq {
+		r = append(r, k)
+	}
+	return r
+}
+
+func TestTrieSet(t *testing.T) {
+	s := NewTrieSet("team", "tea", "ten", "to", "inn", "in")
+	s.Insert("tea", "a")
+	s.Remove("inn", "x")
+
+	if got, want := s.Keys(), []string{"a", "in", "tea", "team", "ten", "to"}; !slices.Equal(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+	if s.Len() != 6 || !s.Contains("tea") || s.Contains("te") || s.Contains("inn") {
+		t.Errorf("Len() or Contains() is wrong")
+	}
+
+	prefixes := []struct {
+		prefix string
+		want   []string
+	}{
+		{"te", []string{"tea", "team", "ten"}},
+		{"tea", []string{"tea", "team"}},
+		{"teams", nil},
+		{"i", []string{"in"}},
+		{"", []string{"a", "in", "tea", "team", "ten", "to"}},
+	}
+	for _, tt := range prefixes {
+		if got := collect(s.WithPrefix(tt.prefix)); !slices.Equal(got, tt.want) {
+			t.Errorf("WithPrefix(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	longest := []struct {
+		s    string
+		want string
+		ok   bool
+	}{
+		{"teammate", "team", true},
+		{"tear", "tea", true},
+		{"te", "", false},
+		{"inside", "in", true},
+	}
+	for _, tt := range longest {
+		if got, ok := s.LongestPrefix(tt.s); got != tt.want || ok != tt.ok {
+			t.Errorf("LongestPrefix(%q) = %q, %v, want %q, %v", tt.s, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if !Equal(TrieSetFrom(s.Set()).Set(), s.Set()) {
+		t.Errorf("TrieSetFrom() does not round-trip")
+	}
+	s.Clear()
+	if s.Len() != 0 {
+		t.Errorf("Clear() left %v", s.Keys())
+	}
+}
@@ -0,0 +1,140 @@
+package sets
+
+import "iter"
+
+// Set is a named set type with methods that delegate to the free functions of
+// this package. Since Set is defined as map[K]struct{}, it can still be passed
+// to any of those functions directly.
+//
+// Set has no Sorted method: K is only comparable, and a method can not add the
+// constraints.Ordered requirement. Use sets.Sorted(s) for sets with ordered
+// keys, or the SortedFunc method with an explicit ordering.
+type Set[K comparable] map[K]struct{}
+
+// Of returns a new set containing the keys.
+func Of[K comparable](keys ...K) Set[K] {
+	s := make(Set[K], len(keys))
+	Insert(s, keys...)
+	return s
+}
+
+// FromSlice returns a new set containing the elements of the slice.
+func FromSlice[E ~[]K, K comparable](elems E) Set[K] {
+	return Of(elems...)
+}
+
+// FromKeys returns a new set containing the keys of the map m.
+func FromKeys[M ~map[K]V, K comparable, V any](m M) Set[K] {
+	s := make(Set[K], len(m))
+	for k := range m {
+		s[k] = struct{}{}
+	}
+	return s
+}
+
+// Len returns the number of keys in s.
+func (s Set[K]) Len() int {
+	return len(s)
+}
+
+// Has checks if there is a key in s.
+func (s Set[K]) Has(k K) bool {
+	return Contains(s, k)
+}
+
+// HasAny checks if any of the keys is in s.
+func (s Set[K]) HasAny(keys ...K) bool {
+	return ContainsAny(s, keys...)
+}
+
+// HasAll checks if all the keys are in s.
+func (s Set[K]) HasAll(keys ...K) bool {
+	return ContainsAll(s, keys...)
+}
+
+// Add inserts the keys into s.
+func (s Set[K]) Add(keys ...K) {
+	Insert(s, keys...)
+}
+
+// Remove removes the keys from s.
+func (s Set[K]) Remove(keys ...K) {
+	Remove(s, keys...)
+}
+
+// Clear removes all keys from s.
+func (s Set[K]) Clear() {
+	Clear(s)
+}
+
+// Clone returns a copy of s.
+func (s Set[K]) Clone() Set[K] {
+	return Clone(s)
+}
+
+// Equal reports whether s and o contain the same keys.
+func (s Set[K]) Equal(o Set[K]) bool {
+	return Equal(s, o)
+}
+
+// Union returns s ∪ o.
+func (s Set[K]) Union(o Set[K]) Set[K] {
+	return Union(s, o)
+}
+
+// Intersection returns s ∩ o.
+func (s Set[K]) Intersection(o Set[K]) Set[K] {
+	return Intersection(s, o)
+}
+
+// Difference returns s - o.
+func (s Set[K]) Difference(o Set[K]) Set[K] {
+	return Difference(s, o)
+}
+
+// SymmetricDifference returns s ∆ o.
+func (s Set[K]) SymmetricDifference(o Set[K]) Set[K] {
+	return SymmetricDifference(s, o)
+}
+
+// IsSubset reports whether s ⊆ o.
+func (s Set[K]) IsSubset(o Set[K]) bool {
+	return IsSubset(s, o)
+}
+
+// IsProperSubset reports whether s ⊂ o.
+func (s Set[K]) IsProperSubset(o Set[K]) bool {
+	return IsProperSubset(s, o)
+}
+
+// IsSuperset reports whether s ⊇ o.
+func (s Set[K]) IsSuperset(o Set[K]) bool {
+	return IsSuperset(s, o)
+}
+
+// IsProperSuperset reports whether s ⊃ o.
+func (s Set[K]) IsProperSuperset(o Set[K]) bool {
+	return IsProperSuperset(s, o)
+}
+
+// IsDisjoint reports whether s and o have no keys in common.
+func (s Set[K]) IsDisjoint(o Set[K]) bool {
+	return IsDisjoint(s, o)
+}
+
+// Keys returns the keys from s as a slice. The keys will be in an
+// indeterminate order.
+func (s Set[K]) Keys() []K {
+	return Keys(s)
+}
+
+// SortedFunc returns the keys from s as a slice sorted as determined by the
+// less function. For sets with ordered keys, use the Sorted function instead.
+func (s Set[K]) SortedFunc(less func(a, b K) bool) []K {
+	return SortedFunc(s, less)
+}
+
+// All returns an iterator over the keys in s.
+func (s Set[K]) All() iter.Seq[K] {
+	return All(s)
+}
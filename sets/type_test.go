@@ -0,0 +1,72 @@
+package sets
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSetOf(t *testing.T) {
+	tests := []struct {
+		keys []int
+		want map[int]struct{}
+	}{
+		{nil, empty},
+		{[]int{1}, set(1)},
+		{[]int{1, 1, 2}, set(1, 2)},
+		{[]int{3, 2, 1}, set(1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(to_string(tt.want), func(t *testing.T) {
+			got := Of(tt.keys...)
+			if !Equal(tt.want, got) {
+				t.Errorf("Of() = %v, want %s", got.Keys(), to_string(tt.want))
+			}
+			if got.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", got.Len(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSetFromKeys(t *testing.T) {
+	got := FromKeys(map[string]int{"a": 1, "b": 2})
+	if !got.HasAll("a", "b") || got.Has("c") || got.Len() != 2 {
+		t.Errorf("FromKeys() = %v", got.SortedFunc(func(a, b string) bool { return a < b }))
+	}
+}
+
+func TestSetMethods(t *testing.T) {
+	a := Of(1, 2, 3)
+	b := FromSlice([]int{2, 3, 4})
+
+	sorted := func(s Set[int]) []int {
+		return s.SortedFunc(func(a, b int) bool { return a < b })
+	}
+	tests := []struct {
+		name string
+		got  Set[int]
+		want []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4}},
+		{"intersection", a.Intersection(b), []int{2, 3}},
+		{"difference", a.Difference(b), []int{1}},
+		{"symmetric difference", a.SymmetricDifference(b), []int{1, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sorted(tt.got); !slices.Equal(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	// free functions accept Set values
+	Insert(a, 5)
+	if !a.Has(5) || !Contains(a, 5) {
+		t.Errorf("Insert() did not add the key to Set")
+	}
+	if !Of(2, 3).IsSubset(a) {
+		t.Errorf("IsSubset() = false, want true")
+	}
+}